@@ -0,0 +1,162 @@
+package linprog
+
+import "math"
+
+// UpperBound gets the upper bound for a variable, which
+// is +Inf if the variable has no upper bound.
+func (s *StandardLP) UpperBound(i int) float64 {
+	if s.UpperBounds == nil {
+		return math.Inf(1)
+	}
+	return s.UpperBounds[i]
+}
+
+// BoundsToRows creates an equivalent program without
+// upper bounds, for use with algorithms that do not
+// support them.
+//
+// Each finite upper bound u_j becomes a constraint
+// x_j + s_j = u_j, where s_j is a new slack variable.
+// The original variables and constraints come first in
+// the new program, followed by the slack variables and
+// bound constraints.
+//
+// If lp has no finite upper bounds, it is returned as-is.
+func (s *StandardLP) BoundsToRows() *StandardLP {
+	bounded := s.boundedVariables()
+	if len(bounded) == 0 {
+		return s
+	}
+
+	numRows := len(s.ConstraintVector) + len(bounded)
+	numCols := s.Dim() + len(bounded)
+	var matrix Matrix
+	if _, ok := s.ConstraintMatrix.(*SparseMatrix); ok {
+		matrix = NewSparseMatrix(numRows, numCols)
+	} else {
+		matrix = NewDenseMatrix(numRows, numCols)
+	}
+	vector := append(Vector{}, s.ConstraintVector...)
+	for row := range s.ConstraintVector {
+		for j, x := range s.ConstraintMatrix.CopyRow(row) {
+			if x != 0 {
+				matrix.Set(row, j, x)
+			}
+		}
+	}
+	for i, variable := range bounded {
+		row := len(s.ConstraintVector) + i
+		matrix.Set(row, variable, 1)
+		matrix.Set(row, s.Dim()+i, 1)
+		vector = append(vector, s.UpperBounds[variable])
+	}
+	return &StandardLP{
+		Objective:        append(append(Vector{}, s.Objective...), make(Vector, len(bounded))...),
+		ConstraintMatrix: matrix,
+		ConstraintVector: vector,
+	}
+}
+
+// fromBoundRows maps a result for the program produced
+// by BoundsToRows back to s, for algorithms that do not
+// support upper bounds.
+//
+// The solution, ray, and duals are truncated to the
+// variables and rows of s, and the reduced costs leave out
+// the duals of the bound rows, like those of
+// SimplexTableau.ReducedCosts. Farkas certificates are
+// dropped, like those of SimplexTableau.FarkasCertificate.
+// The basis is left as a basis of the larger program.
+func (s *StandardLP) fromBoundRows(res *Result) {
+	bounded := s.boundedVariables()
+	if len(bounded) == 0 {
+		return
+	}
+	if res.ReducedCosts != nil {
+		// The slack of a bound row has a reduced cost of
+		// minus the row's dual.
+		for i, variable := range bounded {
+			res.ReducedCosts[variable] -= res.ReducedCosts[s.Dim()+i]
+		}
+		res.ReducedCosts = res.ReducedCosts[:s.Dim()]
+	}
+	if res.Solution != nil {
+		res.Solution = res.Solution[:s.Dim()]
+	}
+	if res.Ray != nil {
+		res.Ray = res.Ray[:s.Dim()]
+	}
+	if res.Duals != nil {
+		res.Duals = res.Duals[:len(s.ConstraintVector)]
+	}
+	res.Farkas = nil
+}
+
+// boundedVariables lists the variables with finite upper
+// bounds, in the order of their rows in BoundsToRows.
+func (s *StandardLP) boundedVariables() []int {
+	var res []int
+	for i, bound := range s.UpperBounds {
+		if !math.IsInf(bound, 1) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// hasUpperBounds checks if any variable has a finite
+// upper bound.
+func (s *StandardLP) hasUpperBounds() bool {
+	for _, bound := range s.UpperBounds {
+		if !math.IsInf(bound, 1) {
+			return true
+		}
+	}
+	return false
+}
+
+// upperBound gets the upper bound for a variable in the
+// tableau. Artificial variables have no upper bound.
+//
+// The bound is the same whether or not the variable is
+// complemented, since u-x ranges over [0, u] just like x.
+func (s *SimplexTableau) upperBound(i int) float64 {
+	if s.lp == nil || i >= s.lp.Dim() {
+		return math.Inf(1)
+	}
+	return s.lp.UpperBound(i)
+}
+
+// complement replaces a non-basic variable x with its
+// complement u-x, where u is the variable's upper bound.
+//
+// This moves the variable to its opposite bound while
+// keeping it at zero in the tableau, so the rest of the
+// simplex method only deals with lower bounds.
+func (s *SimplexTableau) complement(variable int) {
+	bound := s.upperBound(variable)
+	valueCol := s.Matrix.Cols() - 1
+	for row := 0; row < s.Matrix.Rows(); row++ {
+		entry := s.Matrix.At(row, variable)
+		if entry != 0 {
+			s.Matrix.Set(row, valueCol, s.Matrix.At(row, valueCol)-bound*entry)
+			s.Matrix.Set(row, variable, -entry)
+		}
+	}
+	if s.complemented[variable] {
+		delete(s.complemented, variable)
+	} else {
+		if s.complemented == nil {
+			s.complemented = map[int]bool{}
+		}
+		s.complemented[variable] = true
+	}
+}
+
+// complementBasic is like complement, but for a basic
+// variable, whose row is negated so that the complement
+// has a unit column.
+func (s *SimplexTableau) complementBasic(variable int) {
+	s.complement(variable)
+	s.Matrix.ScaleRow(s.BasicToRow[variable], -1)
+}
@@ -0,0 +1,203 @@
+package linprog
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimplexUpperBounds(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(10, 20)
+		problem.UpperBounds = make(Vector, problem.Dim())
+		for j := range problem.UpperBounds {
+			if j%3 == 0 {
+				problem.UpperBounds[j] = math.Inf(1)
+			} else {
+				problem.UpperBounds[j] = 1 + math.Abs(NewVectorRandom(1)[0])
+			}
+		}
+		expected := Simplex(problem.BoundsToRows(), GreedyPivotRule{}, true)
+		for _, rule := range []PivotRule{BlandPivotRule{}, GreedyPivotRule{}} {
+			actual := Simplex(problem, rule, true)
+			if actual.Status != expected.Status {
+				t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+			}
+			switch actual.Status {
+			case Optimal:
+				if math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+					t.Errorf("expected objective %f but got %f", expected.Objective,
+						actual.Objective)
+				}
+				if actual.Duals == nil {
+					t.Error("missing duals")
+				}
+				testBoundedSolution(t, problem, actual.Solution)
+			case Unbounded:
+				if !VerifyRay(problem, actual.Ray) {
+					t.Errorf("invalid ray: %v", actual.Ray)
+				}
+				testBoundedSolution(t, problem, actual.Solution)
+			}
+		}
+	}
+}
+
+func TestSimplexBoundFlips(t *testing.T) {
+	// Every variable is pushed to its upper bound, which
+	// needs no pivots once phase 1 is done.
+	problem := &StandardLP{
+		Objective: Vector{3, 2, 1, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 4,
+			Data:    []float64{1, 1, 1, 1},
+		},
+		ConstraintVector: Vector{10},
+		UpperBounds:      Vector{2, 3, 4, math.Inf(1)},
+	}
+	res := Simplex(problem, BlandPivotRule{}, true)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	if !vectorsEqual(res.Solution, Vector{2, 3, 4, 1}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
+	}
+	if math.Abs(res.Objective-16) > 1e-8 {
+		t.Errorf("unexpected objective: %f", res.Objective)
+	}
+	if !vectorsEqual(res.ReducedCosts, Vector{3, 2, 1, 0}) {
+		t.Errorf("unexpected reduced costs: %v", res.ReducedCosts)
+	}
+
+	problem.UpperBounds[3] = 0.5
+	if res := Simplex(problem, BlandPivotRule{}, true); res.Status != Infeasible {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+
+	problem.Objective[3] = 1
+	problem.ConstraintMatrix.Set(0, 3, 0)
+	problem.UpperBounds[3] = math.Inf(1)
+	problem.ConstraintVector[0] = 1
+	res = Simplex(problem, BlandPivotRule{}, true)
+	if res.Status != Unbounded || !VerifyRay(problem, res.Ray) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Ray)
+	}
+}
+
+func TestUpperBoundsDualSimplex(t *testing.T) {
+	var numPivots int
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(10, 20)
+		problem.UpperBounds = make(Vector, problem.Dim())
+		for j := range problem.UpperBounds {
+			if j%3 == 0 {
+				problem.UpperBounds[j] = math.Inf(1)
+			} else {
+				problem.UpperBounds[j] = 0.5 + math.Abs(NewVectorRandom(1)[0])
+			}
+		}
+
+		// The new RHS comes from a different point within
+		// the bounds.
+		values := NewVectorRandom(problem.Dim()).Abs()
+		for j, x := range values {
+			values[j] = math.Min(x, problem.UpperBounds[j])
+		}
+		changed := *problem
+		changed.ConstraintVector = make(Vector, len(problem.ConstraintVector))
+		for j := range changed.ConstraintVector {
+			changed.ConstraintVector[j] = problem.ConstraintMatrix.CopyRow(j).Dot(values)
+		}
+		expected := Simplex(&changed, GreedyPivotRule{}, true)
+
+		for _, rule := range []DualPivotRule{BlandDualPivotRule{}, GreedyDualPivotRule{}} {
+			tableau, initial := SimplexWithTableau(problem, GreedyPivotRule{}, true)
+			if initial.Status != Optimal {
+				break
+			}
+			if !tableau.SetConstraintVector(changed.ConstraintVector) {
+				t.Fatal("failed to set constraint vector")
+			}
+			actual := DualSimplex(tableau, rule)
+			if actual.Status != expected.Status {
+				t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+			}
+			if actual.Status != Optimal {
+				continue
+			}
+			numPivots += actual.Phase2Iterations
+			if math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+				t.Errorf("expected objective %f but got %f", expected.Objective,
+					actual.Objective)
+			}
+			testBoundedSolution(t, &changed, actual.Solution)
+		}
+	}
+	if numPivots == 0 {
+		t.Error("expected dual pivots")
+	}
+}
+
+func testBoundedSolution(t *testing.T, problem *StandardLP, solution Vector) {
+	for i, x := range solution {
+		if x < -1e-8 || x > problem.UpperBound(i)+1e-8 {
+			t.Errorf("variable %d out of bounds: %f", i, x)
+		}
+	}
+	for i, b := range problem.ConstraintVector {
+		if math.Abs(problem.ConstraintMatrix.CopyRow(i).Dot(solution)-b) > 1e-5 {
+			t.Errorf("constraint %d violated", i)
+		}
+	}
+}
+
+func TestUpperBoundsAsRows(t *testing.T) {
+	// Like TestSimplexBoundFlips, but for the algorithms
+	// which turn upper bounds into rows.
+	problem := &StandardLP{
+		Objective: Vector{3, 2, 1, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 4,
+			Data:    []float64{1, 1, 1, 1},
+		},
+		ConstraintVector: Vector{10},
+		UpperBounds:      Vector{2, 3, 4, math.Inf(1)},
+	}
+	interior := InteriorPoint(problem, nil)
+	_, crossover := Crossover(problem, interior.Solution, GreedyPivotRule{}, true)
+	results := map[string]*Result{
+		"revised":   RevisedSimplex(problem, GreedyPricingRule{}),
+		"interior":  interior,
+		"crossover": crossover,
+	}
+	for name, res := range results {
+		if res.Status != Optimal {
+			t.Errorf("%s: unexpected status: %v", name, res.Status)
+			continue
+		}
+		if !vectorsEqual(res.Solution, Vector{2, 3, 4, 1}) {
+			t.Errorf("%s: unexpected solution: %v", name, res.Solution)
+		}
+		if math.Abs(res.Objective-16) > 1e-5 {
+			t.Errorf("%s: unexpected objective: %f", name, res.Objective)
+		}
+		if !vectorsEqual(res.Duals, Vector{0}) {
+			t.Errorf("%s: unexpected duals: %v", name, res.Duals)
+		}
+		if !vectorsEqual(res.ReducedCosts, Vector{3, 2, 1, 0}) {
+			t.Errorf("%s: unexpected reduced costs: %v", name, res.ReducedCosts)
+		}
+	}
+
+	problem.UpperBounds[3] = 0.5
+	iis := FindIIS(problem, BlandPivotRule{}, true)
+	if iis == nil {
+		t.Fatal("expected an IIS")
+	}
+	if !reflect.DeepEqual(iis.Rows, []int{0}) || len(iis.Bounds) != 0 ||
+		!reflect.DeepEqual(iis.UpperBounds, []int{0, 1, 2, 3}) {
+		t.Errorf("unexpected IIS: %+v", iis)
+	}
+}
@@ -0,0 +1,65 @@
+package linprog
+
+import "math"
+
+// A Cholesky is a Cholesky factorization L*L' of a
+// symmetric positive definite matrix.
+type Cholesky struct {
+	size int
+
+	// data stores L in row-major order.
+	data []float64
+}
+
+// NewCholesky factorizes a symmetric positive definite
+// matrix. Only the lower triangle of the matrix is used.
+//
+// If a non-positive pivot is encountered, the matrix is
+// not positive definite and nil is returned.
+func NewCholesky(m Matrix) *Cholesky {
+	size := m.Rows()
+	if m.Cols() != size {
+		panic("matrix must be square")
+	}
+	res := &Cholesky{size: size, data: make([]float64, size*size)}
+	for i := 0; i < size; i++ {
+		row := m.CopyRow(i)
+		for j := 0; j <= i; j++ {
+			sum := row[j]
+			for k := 0; k < j; k++ {
+				sum -= res.at(i, k) * res.at(j, k)
+			}
+			if i == j {
+				if !(sum > 0) {
+					return nil
+				}
+				res.data[i*size+i] = math.Sqrt(sum)
+			} else {
+				res.data[i*size+j] = sum / res.at(j, j)
+			}
+		}
+	}
+	return res
+}
+
+// Solve solves L*L'*x = b for x.
+func (c *Cholesky) Solve(b Vector) Vector {
+	x := append(Vector{}, b...)
+	for i := 0; i < c.size; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= c.at(i, j) * x[j]
+		}
+		x[i] /= c.at(i, i)
+	}
+	for i := c.size - 1; i >= 0; i-- {
+		for j := i + 1; j < c.size; j++ {
+			x[i] -= c.at(j, i) * x[j]
+		}
+		x[i] /= c.at(i, i)
+	}
+	return x
+}
+
+func (c *Cholesky) at(i, j int) float64 {
+	return c.data[i*c.size+j]
+}
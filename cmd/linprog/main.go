@@ -0,0 +1,372 @@
+// Command linprog solves linear programs from MPS, CPLEX
+// LP, or JSON files.
+//
+// Usage:
+//
+//	linprog [flags] [file]
+//
+// If no file is given, the program is read from standard
+// input. Run linprog -help for a list of flags. Flags which
+// the chosen algorithm cannot honor, such as -time-limit
+// with the exact algorithm, are rejected.
+//
+// The exit code reflects the status of the solve:
+//
+//	0  optimal
+//	1  error, such as an unreadable file
+//	2  infeasible
+//	3  unbounded
+//	4  stopped early by a limit or an interrupt
+//	5  numerical failure
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/unixpickle/linprog"
+)
+
+const (
+	exitOptimal = iota
+	exitError
+	exitInfeasible
+	exitUnbounded
+	exitStopped
+	exitNumericalFailure
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// algorithmFlags lists the flags which each algorithm
+// honors, besides -format, -algorithm, and -duals.
+var algorithmFlags = map[string][]string{
+	"simplex": {"pivot", "ratio", "dense", "perturb", "max-iter", "time-limit", "log"},
+	"revised": {"pivot"},
+	"ipm":     {"max-iter", "crossover", "pivot", "ratio", "dense"},
+	"exact":   {},
+}
+
+// crossoverFlags lists the flags which the ipm algorithm
+// only honors with -crossover.
+var crossoverFlags = map[string]bool{"pivot": true, "ratio": true, "dense": true}
+
+type flags struct {
+	Format       string
+	Algorithm    string
+	Pivot        string
+	Ratio        string
+	Dense        bool
+	Perturbation float64
+	MaxIter      int
+	TimeLimit    time.Duration
+	LogFrequency int
+	Duals        bool
+	Crossover    bool
+}
+
+// run runs the command and returns its exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var f flags
+	fs := flag.NewFlagSet("linprog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&f.Format, "format", "auto", "input format: auto, mps, freemps, lp, or json")
+	fs.StringVar(&f.Algorithm, "algorithm", "simplex",
+		"algorithm: simplex, revised, ipm, or exact")
+	fs.StringVar(&f.Pivot, "pivot", "greedy", "pivot rule: bland, greedy, steepest, or devex")
+	fs.StringVar(&f.Ratio, "ratio", "standard", "ratio test: standard, harris, or lex")
+	fs.BoolVar(&f.Dense, "dense", false, "use a dense tableau")
+	fs.Float64Var(&f.Perturbation, "perturb", 0, "relative size of the RHS perturbation")
+	fs.IntVar(&f.MaxIter, "max-iter", 0, "maximum number of iterations (0 for no limit)")
+	fs.DurationVar(&f.TimeLimit, "time-limit", 0, "maximum solve time (0 for no limit)")
+	fs.IntVar(&f.LogFrequency, "log", 0, "log progress to stderr every N iterations")
+	fs.BoolVar(&f.Duals, "duals", false, "print dual values and reduced costs")
+	fs.BoolVar(&f.Crossover, "crossover", false, "run crossover after the ipm algorithm")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: linprog [flags] [file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitError
+	}
+	if err := checkFlags(fs, &f); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	input := stdin
+	filename := ""
+	if fs.NArg() == 1 {
+		filename = fs.Arg(0)
+		file, err := os.Open(filename)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		defer file.Close()
+		input = file
+	}
+	model, err := readModel(input, f.Format, filename)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	solution, err := solve(ctx, model, &f, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	printSolution(stdout, model, solution, f.Duals)
+
+	switch solution.Status() {
+	case linprog.Optimal:
+		return exitOptimal
+	case linprog.Infeasible:
+		return exitInfeasible
+	case linprog.Unbounded:
+		return exitUnbounded
+	case linprog.NumericalFailure:
+		return exitNumericalFailure
+	default:
+		return exitStopped
+	}
+}
+
+// checkFlags makes sure that the chosen algorithm honors
+// every flag which was set, so that a limit is never
+// silently ignored.
+func checkFlags(fs *flag.FlagSet, f *flags) error {
+	supported, ok := algorithmFlags[f.Algorithm]
+	if !ok {
+		return fmt.Errorf("unknown algorithm: %s", f.Algorithm)
+	}
+	allowed := map[string]bool{"format": true, "algorithm": true, "duals": true}
+	for _, name := range supported {
+		allowed[name] = true
+	}
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		if !allowed[fl.Name] {
+			err = fmt.Errorf("the %s algorithm does not support -%s", f.Algorithm, fl.Name)
+		} else if f.Algorithm == "ipm" && !f.Crossover && crossoverFlags[fl.Name] {
+			err = fmt.Errorf("the ipm algorithm only supports -%s with -crossover", fl.Name)
+		}
+	})
+	return err
+}
+
+// readModel reads a model in the given format.
+//
+// If the format is "auto", it is guessed from the file
+// extension, or from the contents if there is no file.
+// Files with a .mps extension are read as fixed MPS if
+// possible, and as free MPS otherwise.
+func readModel(r io.Reader, format, filename string) (*linprog.Model, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	auto := format == "auto"
+	if auto {
+		format = guessFormat(data, filename)
+	}
+	switch format {
+	case "mps":
+		model, err := linprog.ReadMPS(bytes.NewReader(data))
+		if err != nil && auto {
+			// Many files with a .mps extension are not
+			// aligned to the columns of fixed MPS.
+			return linprog.ReadFreeMPS(bytes.NewReader(data))
+		}
+		return model, err
+	case "freemps":
+		return linprog.ReadFreeMPS(bytes.NewReader(data))
+	case "lp":
+		return linprog.ReadLP(bytes.NewReader(data))
+	case "json":
+		model := linprog.NewModel()
+		if err := model.UnmarshalJSON(data); err != nil {
+			return nil, err
+		}
+		return model, nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// guessFormat picks a format from a file extension, or by
+// looking at the data if the extension is unknown.
+//
+// MPS files without an extension are assumed to be free
+// MPS, since it also reads most fixed MPS files.
+func guessFormat(data []byte, filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mps":
+		return "mps"
+	case ".lp":
+		return "lp"
+	case ".json":
+		return "json"
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '*' || line[0] == '\\' {
+			continue
+		}
+		if line[0] == '{' {
+			return "json"
+		}
+		word := strings.ToUpper(strings.Fields(line)[0])
+		if word == "NAME" || word == "ROWS" {
+			return "freemps"
+		}
+		break
+	}
+	return "lp"
+}
+
+// solve compiles and solves the model with the algorithm
+// chosen by the flags.
+//
+// Only the simplex algorithm can be stopped early, so an
+// interrupt cancels it and otherwise stops the process.
+func solve(ctx context.Context, model *linprog.Model, f *flags,
+	logOutput io.Writer) (*linprog.ModelSolution, error) {
+	lp, mapping := model.Compile()
+	rt, err := linprog.ParseRatioTest(f.Ratio)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := linprog.ParsePivotRule(f.Pivot, rt)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *linprog.Result
+	switch f.Algorithm {
+	case "simplex":
+		ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+		opts := &linprog.Options{
+			Perturbation:  f.Perturbation,
+			Context:       ctx,
+			MaxIterations: f.MaxIter,
+		}
+		if f.TimeLimit != 0 {
+			opts.Deadline = time.Now().Add(f.TimeLimit)
+		}
+		if f.LogFrequency > 0 {
+			opts.Progress = linprog.NewProgressLogger(logOutput, f.LogFrequency)
+		}
+		res = linprog.SimplexWithOptions(lp, pr, f.Dense, opts)
+	case "revised":
+		pricing, err := linprog.ParsePricingRule(f.Pivot)
+		if err != nil {
+			return nil, err
+		}
+		res = linprog.RevisedSimplex(lp, pricing)
+	case "ipm":
+		opts := &linprog.InteriorPointOptions{MaxIterations: f.MaxIter}
+		res = linprog.InteriorPoint(lp, opts)
+		if f.Crossover && res.Status == linprog.Optimal {
+			_, res = linprog.Crossover(lp, res.Solution, pr, f.Dense)
+		}
+	case "exact":
+		res = exactResult(linprog.ExactSimplex(lp))
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %s", f.Algorithm)
+	}
+	return model.Solution(mapping, res), nil
+}
+
+// exactResult converts an exact result to floating point.
+func exactResult(e *linprog.ExactResult) *linprog.Result {
+	res := &linprog.Result{
+		Status:           e.Status,
+		Phase1Iterations: e.Phase1Iterations,
+		Phase2Iterations: e.Phase2Iterations,
+		Basis:            e.Basis,
+	}
+	if e.Solution != nil {
+		res.Solution = e.Solution.Float()
+		res.Objective, _ = e.Objective.Float64()
+	}
+	if e.Duals != nil {
+		res.Duals = e.Duals.Float()
+	}
+	if e.Ray != nil {
+		res.Ray = e.Ray.Float()
+	}
+	if e.Farkas != nil {
+		res.Farkas = e.Farkas.Float()
+	}
+	return res
+}
+
+func printSolution(w io.Writer, model *linprog.Model, s *linprog.ModelSolution, duals bool) {
+	res := s.Result
+	fmt.Fprintf(w, "Status: %v\n", res.Status)
+	fmt.Fprintf(w, "Iterations: %d (phase 1), %d (phase 2)\n", res.Phase1Iterations,
+		res.Phase2Iterations)
+	if s.Values == nil {
+		return
+	}
+	fmt.Fprintf(w, "Objective: %v\n", s.Objective)
+
+	fmt.Fprintln(w)
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if duals && s.ReducedCosts != nil {
+		fmt.Fprintln(table, "Variable\tValue\tReduced Cost")
+	} else {
+		fmt.Fprintln(table, "Variable\tValue")
+	}
+	for _, v := range model.Vars() {
+		fmt.Fprintf(table, "%s\t%v", model.VarName(v), cleanZero(s.Value(v)))
+		if duals && s.ReducedCosts != nil {
+			fmt.Fprintf(table, "\t%v", cleanZero(s.ReducedCost(v)))
+		}
+		fmt.Fprintln(table)
+	}
+	table.Flush()
+
+	if duals && s.Duals != nil {
+		fmt.Fprintln(w)
+		table = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(table, "Constraint\tActivity\tDual")
+		for _, name := range model.ConstraintNames() {
+			fmt.Fprintf(table, "%s\t%v\t%v\n", name, cleanZero(s.Activity(name)),
+				cleanZero(s.Dual(name)))
+		}
+		table.Flush()
+	}
+}
+
+// cleanZero turns negative zero into zero, so that it is
+// not printed with a sign.
+func cleanZero(x float64) float64 {
+	if x == 0 {
+		return math.Abs(x)
+	}
+	return x
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModel = `Maximize
+ obj: 3 x + 2 y
+Subject To
+ total: x + y <= 4
+ ratio: x + 3 y <= 6
+Bounds
+ x <= 3
+End
+`
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.lp")
+	if err := os.WriteFile(path, []byte(testModel), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{path},
+		{"-pivot", "bland", "-ratio", "lex", path},
+		{"-pivot", "steepest", "-ratio", "harris", "-dense", path},
+		{"-pivot", "devex", "-perturb", "1e-6", "-log", "1", path},
+		{"-algorithm", "revised", path},
+		{"-algorithm", "ipm", "-crossover", "-pivot", "devex", path},
+		{"-algorithm", "exact", path},
+	} {
+		stdout, stderr, code := runTest(args, "")
+		if code != exitOptimal {
+			t.Errorf("%v: unexpected exit code %d: %s", args, code, stderr)
+			continue
+		}
+		if !strings.Contains(stdout, "Status: Optimal") ||
+			!strings.Contains(stdout, "Objective: 11") {
+			t.Errorf("%v: unexpected output:\n%s", args, stdout)
+		}
+	}
+
+	// The optimum of testModel is degenerate, so its duals
+	// are not unique. Without the bound on x, they are.
+	input := strings.Replace(testModel, "Bounds\n x <= 3\n", "", 1)
+	stdout, _, code := runTest([]string{"-duals", "-format", "lp"}, input)
+	if code != exitOptimal {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	for _, line := range []string{"Variable  Value  Reduced Cost", "x         4      0",
+		"y         0      -1", "Constraint  Activity  Dual", "total       4         3",
+		"ratio       4         0"} {
+		if !strings.Contains(stdout, line) {
+			t.Errorf("missing %q in output:\n%s", line, stdout)
+		}
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	inputs := map[string]string{
+		"json": `{"sense": "min", "objective": [1, 2], "rhs": [3],
+			"matrix": {"rows": [0, 0], "cols": [0, 1], "values": [1, 1]}}`,
+		"mps": "NAME\nROWS\n N COST\n E R\nCOLUMNS\n X COST 1 R 1\n Y COST 2 R 1\n" +
+			"RHS\n R 3\nENDATA\n",
+		"lp": "Minimize\n x + 2 y\nSubject To\n x + y = 3\nEnd\n",
+	}
+	for name, input := range inputs {
+		stdout, stderr, code := runTest(nil, input)
+		if code != exitOptimal || !strings.Contains(stdout, "Objective: 3") {
+			t.Errorf("%s: unexpected result %d:\n%s%s", name, code, stdout, stderr)
+		}
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	cases := []struct {
+		args  []string
+		input string
+		code  int
+	}{
+		{nil, "Maximize\n x\nSubject To\n x >= 1\nEnd\n", exitUnbounded},
+		{nil, "Maximize\n x\nSubject To\n x <= -1\nEnd\n", exitInfeasible},
+		{[]string{"-max-iter", "1"}, testModel, exitStopped},
+		{[]string{"-format", "mps"}, testModel, exitError},
+		{[]string{"-pivot", "random"}, testModel, exitError},
+		{[]string{"-algorithm", "revised", "-pivot", "devex"}, testModel, exitError},
+		{[]string{"-algorithm", "revised", "-ratio", "harris"}, testModel, exitError},
+		{[]string{"-algorithm", "revised", "-time-limit", "1s"}, testModel, exitError},
+		{[]string{"-algorithm", "exact", "-max-iter", "10"}, testModel, exitError},
+		{[]string{"-algorithm", "exact", "-log", "1"}, testModel, exitError},
+		{[]string{"-algorithm", "ipm", "-perturb", "1e-6"}, testModel, exitError},
+		{[]string{"-algorithm", "ipm", "-pivot", "bland"}, testModel, exitError},
+		{[]string{"-algorithm", "newton"}, testModel, exitError},
+		{[]string{"-unknown"}, testModel, exitError},
+		{[]string{"missing.lp"}, "", exitError},
+	}
+	for _, c := range cases {
+		if _, _, code := runTest(c.args, c.input); code != c.code {
+			t.Errorf("%v: expected exit code %d but got %d", c.args, c.code, code)
+		}
+	}
+}
+
+func runTest(args []string, input string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(input), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
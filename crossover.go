@@ -0,0 +1,137 @@
+package linprog
+
+import (
+	"math"
+	"sort"
+)
+
+// Crossover turns an approximate optimal solution x, such
+// as one found by InteriorPoint, into an optimal basic
+// solution.
+//
+// A basis is chosen from the variables with the largest
+// values in x. Then, each non-basic variable which is
+// positive in x is pushed to zero, or into the basis if a
+// basic variable reaches zero first. Pushes go in the
+// direction which improves the objective when possible.
+// Finally, the simplex method is run from the resulting
+// basis with the given pivot rule, which usually takes
+// only a few pivots.
+//
+// Pushes which change the basis are counted as phase 1
+// iterations. If x does not lead to a feasible basis, the
+// program is solved from scratch.
+//
+// The final tableau is returned along with the result, so
+// that it can be used for further analysis. It is nil if
+// no feasible basis was found.
+//
+// Upper bounds are turned into rows with BoundsToRows. In
+// that case, the tableau and the basis belong to the
+// larger program, and the rest of the result is mapped
+// back to lp.
+func Crossover(lp *StandardLP, x Vector, pr PivotRule, dense bool) (*SimplexTableau,
+	*Result) {
+	system := lp.BoundsToRows()
+	if system != lp {
+		// The slack of each bound row takes up the rest of
+		// the bound.
+		x = append(Vector{}, x...)
+		for _, variable := range lp.boundedVariables() {
+			x = append(x, math.Max(0, lp.UpperBounds[variable]-x[variable]))
+		}
+	}
+	tableau, res := crossover(system, x, pr, dense)
+	lp.fromBoundRows(res)
+	return tableau, res
+}
+
+func crossover(lp *StandardLP, x Vector, pr PivotRule, dense bool) (*SimplexTableau,
+	*Result) {
+	order := make([]int, lp.Dim())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return x[order[i]] > x[order[j]]
+	})
+
+	res := &Result{}
+	tableau := NewTableauBasis(lp, order)
+	if tableau != nil {
+		tableau.pushSuperbasics(x, &res.Phase1Iterations)
+	}
+	return finishSimplex(lp, tableau, pr, dense, res), res
+}
+
+// pushSuperbasics moves every non-basic variable with a
+// positive value in x to zero or into the basis, keeping
+// the constraints satisfied.
+func (s *SimplexTableau) pushSuperbasics(x Vector, iterations *int) {
+	values := make(Vector, len(x))
+	for i, value := range x {
+		if !s.Basic(i) {
+			values[i] = math.Max(0, value)
+		}
+	}
+	epsilon := relativeEpsilon * s.Matrix.AbsMax()
+	for variable, value := range values {
+		if value == 0 {
+			continue
+		}
+		basicValues := s.basicValues(values)
+		column := s.Matrix.CopyCol(variable)
+
+		direction := -1.0
+		row, step := s.pushLimit(column, basicValues, direction, epsilon)
+		if s.Cost(variable) > 0 {
+			upRow, upStep := s.pushLimit(column, basicValues, 1, epsilon)
+			if upRow != -1 {
+				direction, row, step = 1, upRow, upStep
+			}
+		}
+
+		values[variable] = 0
+		if direction < 0 && step >= value {
+			continue
+		}
+		s.swapBasis(s.RowToBasic[row], variable)
+		*iterations++
+	}
+}
+
+// basicValues computes the value of the basic variable in
+// each row when the non-basic variables take the given
+// values.
+func (s *SimplexTableau) basicValues(values Vector) Vector {
+	res := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	for variable, value := range values {
+		if value != 0 {
+			res.Add(s.Matrix.CopyCol(variable), -value)
+		}
+	}
+	return res
+}
+
+// pushLimit finds the row whose basic variable is the
+// first to reach zero as a non-basic variable moves in a
+// direction, along with the distance it can move.
+//
+// If no basic variable limits the move, the row is -1 and
+// the distance is infinite.
+func (s *SimplexTableau) pushLimit(column, basicValues Vector, direction,
+	epsilon float64) (int, float64) {
+	limitRow := -1
+	limit := math.Inf(1)
+	for row := range s.RowToBasic {
+		rate := direction * column[row]
+		if rate > epsilon {
+			distance := math.Max(0, basicValues[row]) / rate
+			if distance < limit {
+				limitRow = row
+				limit = distance
+			}
+		}
+	}
+	return limitRow, limit
+}
@@ -0,0 +1,65 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCrossover(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(15, 30)
+		interior := InteriorPoint(problem, nil)
+		if interior.Status != Optimal {
+			continue
+		}
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		tableau, actual := Crossover(problem, interior.Solution, GreedyPivotRule{}, true)
+		if actual.Status != Optimal {
+			t.Fatalf("unexpected status: %v", actual.Status)
+		}
+		if tableau == nil || actual.Sensitivity == nil {
+			t.Fatal("missing tableau or sensitivity")
+		}
+		if math.Abs(actual.Objective-expected.Objective) > 1e-5*(1+math.Abs(expected.Objective)) {
+			t.Errorf("expected objective %f but got %f", expected.Objective, actual.Objective)
+		}
+		var numBasic int
+		for i, x := range actual.Solution {
+			if x < -1e-8 {
+				t.Errorf("negative solution value: %f", x)
+			} else if tableau.Basic(i) {
+				numBasic++
+			} else if x != 0 {
+				t.Errorf("non-basic variable %d has value %f", i, x)
+			}
+		}
+		if numBasic > len(problem.ConstraintVector) {
+			t.Errorf("too many basic variables: %d", numBasic)
+		}
+	}
+}
+
+func TestCrossoverOptimalFace(t *testing.T) {
+	// Every point with x1+x2 = 1 is optimal, so the interior
+	// point method ends in the middle of the optimal face.
+	problem := &StandardLP{
+		Objective: Vector{1, 1, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 3,
+			Data:    []float64{1, 1, 1},
+		},
+		ConstraintVector: Vector{1},
+	}
+	interior := InteriorPoint(problem, nil)
+	if interior.Status != Optimal || math.Abs(interior.Solution[0]-0.5) > 1e-3 {
+		t.Fatalf("unexpected interior result: %v %v", interior.Status, interior.Solution)
+	}
+	_, res := Crossover(problem, interior.Solution, BlandPivotRule{}, true)
+	if res.Status != Optimal || math.Abs(res.Objective-1) > 1e-8 {
+		t.Fatalf("unexpected result: %v %v", res.Status, res.Objective)
+	}
+	if !vectorsEqual(res.Solution, Vector{1, 0, 0}) && !vectorsEqual(res.Solution, Vector{0, 1, 0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
+	}
+}
@@ -0,0 +1,164 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+// bealeProgram creates Beale's example, on which the
+// simplex method cycles with Dantzig's rule if ties in the
+// ratio test go to the lowest-indexed variable.
+func bealeProgram() *StandardLP {
+	return &StandardLP{
+		Objective: Vector{0, 0, 0, 0.75, -20, 0.5, -6},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 3,
+			NumCols: 7,
+			Data: []float64{
+				1, 0, 0, 0.25, -8, -1, 9,
+				0, 1, 0, 0.5, -12, -0.5, 3,
+				0, 0, 1, 0, 0, 1, 0,
+			},
+		},
+		ConstraintVector: Vector{0, 0, 1},
+	}
+}
+
+// lowestIndexRatioTest is the standard ratio test, with
+// ties going to the lowest-indexed basic variable.
+type lowestIndexRatioTest struct{}
+
+func (l lowestIndexRatioTest) ChooseLeaving(s *SimplexTableau, entering int) int {
+	leaveVar := -1
+	minRatio := math.Inf(1)
+	for basic, row := range s.BasicToRow {
+		entry := s.Matrix.At(row, entering)
+		if entry <= 0 {
+			continue
+		}
+		ratio := s.Matrix.At(row, s.Matrix.Cols()-1) / entry
+		if ratio < minRatio || (ratio == minRatio && basic < leaveVar) {
+			leaveVar = basic
+			minRatio = ratio
+		}
+	}
+	return leaveVar
+}
+
+func TestBealeCycling(t *testing.T) {
+	problem := bealeProgram()
+	expected := ExactSimplex(problem)
+	expectedObjective, _ := expected.Objective.Float64()
+
+	solve := func(rule PivotRule) SimplexStatus {
+		tableau := NewTableauBasis(problem, []int{0, 1, 2})
+		for i := 0; i < 100; i++ {
+			leaving, entering, status := rule.ChoosePivot(tableau)
+			if status != Working {
+				return status
+			}
+			tableau.Pivot(leaving, entering)
+		}
+		return IterationLimit
+	}
+	if status := solve(GreedyPivotRule{RatioTest: lowestIndexRatioTest{}}); status !=
+		IterationLimit {
+		t.Errorf("expected cycling but got status %v", status)
+	}
+
+	tableau := NewTableauBasis(problem, []int{0, 1, 2})
+	var iterations int
+	status, _ := runSimplex(tableau, GreedyPivotRule{RatioTest: LexicographicRatioTest{}},
+		&iterations)
+	if status != Optimal {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	if math.Abs(tableau.ObjectiveValue()-expectedObjective) > 1e-8 {
+		t.Errorf("expected objective %f but got %f", expectedObjective,
+			tableau.ObjectiveValue())
+	}
+}
+
+func TestSimplexPerturbation(t *testing.T) {
+	problem := bealeProgram()
+	expected := ExactSimplex(problem)
+	expectedObjective, _ := expected.Objective.Float64()
+	opts := &Options{Perturbation: 1e-6}
+	res := SimplexWithOptions(problem, GreedyPivotRule{RatioTest: lowestIndexRatioTest{}},
+		true, opts)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	if !res.Perturbed {
+		t.Error("perturbation was not used")
+	}
+	if math.Abs(res.Objective-expectedObjective) > 1e-8 {
+		t.Errorf("expected objective %f but got %f", expectedObjective, res.Objective)
+	}
+	for i, x := range problem.ConstraintVector {
+		if math.Abs(problem.ConstraintMatrix.CopyRow(i).Dot(res.Solution)-x) > 1e-8 {
+			t.Errorf("constraint %d is violated", i)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(15, 30)
+		if i%2 == 1 {
+			problem.UpperBounds = make(Vector, problem.Dim())
+			for j := range problem.UpperBounds {
+				problem.UpperBounds[j] = 0.5 + math.Abs(NewVectorRandom(1)[0])
+			}
+		}
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		actual := SimplexWithOptions(problem, GreedyPivotRule{}, true, opts)
+		if actual.Status != expected.Status {
+			t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+		}
+		if expected.Status == Optimal {
+			if !actual.Perturbed {
+				t.Error("perturbation was not used")
+			}
+			if math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+				t.Errorf("expected objective %f but got %f", expected.Objective,
+					actual.Objective)
+			}
+		}
+	}
+}
+
+func TestSimplexPhase1Perturbation(t *testing.T) {
+	opts := &Options{Perturbation: 1e-6}
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(15, 30)
+		if i%2 == 1 {
+			problem.UpperBounds = make(Vector, problem.Dim())
+			for j := range problem.UpperBounds {
+				problem.UpperBounds[j] = 0.5 + math.Abs(NewVectorRandom(1)[0])
+			}
+		}
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		tableau, res := SimplexPhase1WithOptions(problem, GreedyPivotRule{}, true, opts)
+		if tableau == nil {
+			if res.Status != expected.Status || res.Perturbed {
+				t.Errorf("expected status %v but got %v (perturbed %v)", expected.Status,
+					res.Status, res.Perturbed)
+			}
+			continue
+		}
+		if !res.Perturbed {
+			t.Error("perturbation was not used")
+		}
+		if !tableau.primalFeasible(1e-8) {
+			t.Fatal("tableau is not feasible for the original program")
+		}
+		var iterations int
+		status, _ := runSimplex(tableau, GreedyPivotRule{}, &iterations)
+		if status != expected.Status {
+			t.Fatalf("expected status %v but got %v", expected.Status, status)
+		}
+		if status == Optimal && math.Abs(tableau.ObjectiveValue()-expected.Objective) > 1e-5 {
+			t.Errorf("expected objective %f but got %f", expected.Objective,
+				tableau.ObjectiveValue())
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package linprog
+
+import "math"
+
+// A DualPivotRule is a rule for determining which pivot
+// to make in each iteration of the dual simplex method.
+//
+// It also indicates if the algorithm should halt.
+// If the status is Infeasible, the leaving variable is
+// a basic variable outside of its bounds which cannot be
+// moved back within them, and the entering variable is -1.
+//
+// A basic variable above its upper bound leaves the basis
+// at that bound.
+type DualPivotRule interface {
+	ChooseDualPivot(s *SimplexTableau) (leaving, entering int, status SimplexStatus)
+}
+
+// BlandDualPivotRule is a DualPivotRule that picks the
+// out-of-bounds basic variable with the lowest index,
+// which prevents cycling.
+type BlandDualPivotRule struct{}
+
+func (b BlandDualPivotRule) ChooseDualPivot(s *SimplexTableau) (int, int, SimplexStatus) {
+	epsilon := relativeEpsilon * s.Matrix.AbsMax()
+	leaveVar := -1
+	for basic, row := range s.BasicToRow {
+		if s.boundViolation(basic, s.Matrix.At(row, s.Matrix.Cols()-1)) > epsilon {
+			if leaveVar == -1 || basic < leaveVar {
+				leaveVar = basic
+			}
+		}
+	}
+	return dualPivotForLeaving(s, leaveVar)
+}
+
+// GreedyDualPivotRule is a DualPivotRule that picks the
+// basic variable furthest outside of its bounds to leave.
+type GreedyDualPivotRule struct{}
+
+func (g GreedyDualPivotRule) ChooseDualPivot(s *SimplexTableau) (int, int, SimplexStatus) {
+	epsilon := relativeEpsilon * s.Matrix.AbsMax()
+	leaveVar := -1
+	worstViolation := epsilon
+	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	for basic, row := range s.BasicToRow {
+		if violation := s.boundViolation(basic, values[row]); violation > worstViolation {
+			leaveVar = basic
+			worstViolation = violation
+		}
+	}
+	return dualPivotForLeaving(s, leaveVar)
+}
+
+// boundViolation measures how far a basic value lies
+// outside of the variable's bounds.
+func (s *SimplexTableau) boundViolation(basic int, value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return math.Max(0, value-s.upperBound(basic))
+}
+
+func dualPivotForLeaving(s *SimplexTableau, leaveVar int) (int, int, SimplexStatus) {
+	if leaveVar == -1 {
+		return 0, 0, Optimal
+	}
+	enterVar := minDualRatioEnterVariable(s, leaveVar)
+	if enterVar == -1 {
+		return leaveVar, -1, Infeasible
+	}
+	return leaveVar, enterVar, Working
+}
+
+// minDualRatioEnterVariable performs the dual ratio test,
+// finding the entering variable which keeps every
+// relative cost coefficient non-positive.
+func minDualRatioEnterVariable(s *SimplexTableau, leaveVar int) int {
+	enterVar := -1
+	minRatio := math.Inf(1)
+	row := s.BasicToRow[leaveVar]
+	entries := s.Matrix.CopyRow(row)
+	if s.Matrix.At(row, s.Matrix.Cols()-1) > 0 {
+		// The variable leaves at its upper bound, so the
+		// test applies to the row of its complement.
+		entries.Scale(-1)
+	}
+	costs := s.Costs()
+	epsilon := relativeEpsilon * entries.AbsMax()
+	for i, cost := range costs {
+		entry := entries[i]
+		if entry < -epsilon && !s.Basic(i) {
+			ratio := math.Min(0, cost) / entry
+			if ratio < minRatio {
+				minRatio = ratio
+				enterVar = i
+			}
+		}
+	}
+	return enterVar
+}
+
+// DualSimplex runs the dual simplex method on a phase 2
+// tableau to completion.
+//
+// The tableau must be dual feasible, meaning that all of
+// its relative cost coefficients are non-positive, as is
+// the case for an optimal tableau from SimplexWithTableau
+// whose constraint vector was changed with
+// SetConstraintVector.
+// Each pivot keeps the tableau dual feasible while making
+// progress towards basic values within their bounds, so
+// no phase 1 is needed.
+//
+// Iterations are counted as phase 2 iterations.
+// If the program is infeasible, the result includes a
+// Farkas certificate, unless the program has upper bounds.
+func DualSimplex(t *SimplexTableau, pr DualPivotRule) *Result {
+	res := &Result{}
+	status, leaving := runDualSimplex(t, pr, &res.Phase2Iterations)
+	t.fillResult(res, status, -1)
+	if status == Infeasible {
+		res.Farkas = t.rowCertificate(t.BasicToRow[leaving])
+	}
+	return res
+}
+
+// runDualSimplex pivots until the pivot rule indicates
+// that the algorithm should stop, returning the final
+// status.
+// If the status is Infeasible, it also returns the basic
+// variable which proves infeasibility.
+func runDualSimplex(t *SimplexTableau, pr DualPivotRule, iterations *int) (SimplexStatus, int) {
+	for {
+		leaving, entering, status := pr.ChooseDualPivot(t)
+		if status != Working {
+			return status, leaving
+		}
+		row := t.BasicToRow[leaving]
+		coeff := t.Matrix.At(row, entering)
+		if coeff == 0 || math.IsNaN(coeff) || math.IsInf(coeff, 0) {
+			return NumericalFailure, -1
+		}
+		if t.Matrix.At(row, t.Matrix.Cols()-1) > 0 {
+			t.complementBasic(leaving)
+		}
+		t.swapBasis(leaving, entering)
+		*iterations++
+	}
+}
+
+// SetConstraintVector changes the right-hand side of the
+// program underlying a phase 2 tableau while keeping the
+// current basis.
+//
+// The relative cost coefficients do not depend on the
+// right-hand side, so an optimal tableau remains dual
+// feasible and DualSimplex can be used to re-optimize it.
+//
+// Variables at their upper bounds stay there.
+//
+// It returns false if the basis is numerically singular,
+// or if b is inconsistent with rows that were dropped as
+// redundant. In either case, the tableau is unchanged.
+func (s *SimplexTableau) SetConstraintVector(b Vector) bool {
+	lu := s.basisLU()
+	if lu == nil {
+		return false
+	}
+	// Non-basic variables at their upper bounds move the
+	// right-hand side of the basic variables.
+	objective := 0.0
+	rhs := append(Vector{}, b...)
+	for variable := range s.complemented {
+		if s.Basic(variable) {
+			continue
+		}
+		bound := s.upperBound(variable)
+		rhs.Add(s.lp.ConstraintMatrix.CopyCol(variable), -bound)
+		objective += s.lp.Objective[variable] * bound
+	}
+	values := lu.Solve(rhs)
+	epsilon := relativeEpsilon * math.Max(1, rhs.AbsMax())
+	for row, value := range values {
+		if _, ok := s.RowToBasic[row]; !ok && math.Abs(value) > epsilon {
+			return false
+		}
+	}
+
+	lp := *s.lp
+	lp.ConstraintVector = append(Vector{}, b...)
+	s.lp = &lp
+
+	valueCol := s.Matrix.Cols() - 1
+	for row, basic := range s.RowToBasic {
+		value := values[row]
+		objective += lp.Objective[basic] * value
+		if s.complemented[basic] {
+			// The tableau stores u-x for the variable.
+			value = s.upperBound(basic) - value
+		}
+		s.Matrix.Set(row, valueCol, value)
+	}
+	s.Matrix.Set(s.Matrix.Rows()-1, valueCol, -objective)
+	return true
+}
+
+// rowCertificate creates a Farkas certificate from a
+// tableau row whose basic value is negative but whose
+// entries are all non-negative.
+//
+// The row is w'*[A b] for w = B^-1'*e_row, so y = -w
+// satisfies y'*A <= 0 and y'*b > 0.
+//
+// Like FarkasCertificate, it returns nil if the program
+// has upper bounds.
+func (s *SimplexTableau) rowCertificate(row int) Vector {
+	if s.lp.hasUpperBounds() {
+		return nil
+	}
+	lu := s.basisLU()
+	if lu == nil {
+		return nil
+	}
+	unit := make(Vector, len(s.lp.ConstraintVector))
+	unit[row] = -1
+	res := lu.SolveTranspose(unit)
+	if dot := res.Dot(s.lp.ConstraintVector); dot > 0 {
+		res.Scale(1 / dot)
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDualSimplex(t *testing.T) {
+	for _, rule := range []DualPivotRule{BlandDualPivotRule{}, GreedyDualPivotRule{}} {
+		problem := &StandardLP{
+			Objective: Vector{3, 5, 0, 0, 0},
+			ConstraintMatrix: &DenseMatrix{
+				NumRows: 3,
+				NumCols: 5,
+				Data: []float64{
+					1, 0, 1, 0, 0,
+					0, 2, 0, 1, 0,
+					3, 2, 0, 0, 1,
+				},
+			},
+			ConstraintVector: Vector{4, 12, 18},
+		}
+		tableau, initial := SimplexWithTableau(problem, GreedyPivotRule{}, true)
+		if initial.Status != Optimal {
+			t.Fatalf("unexpected status: %v", initial.Status)
+		}
+
+		tightened := Vector{4, 12, 9}
+		if !tableau.SetConstraintVector(tightened) {
+			t.Fatal("failed to set constraint vector")
+		}
+		res := DualSimplex(tableau, rule)
+		problem.ConstraintVector = tightened
+		expected := Simplex(problem, BlandPivotRule{}, false)
+		if res.Status != Optimal {
+			t.Fatalf("unexpected status: %v", res.Status)
+		}
+		if res.Phase2Iterations == 0 {
+			t.Error("expected dual pivots")
+		}
+		if math.Abs(res.Objective-expected.Objective) > 1e-5 {
+			t.Errorf("expected objective %f but got %f", expected.Objective, res.Objective)
+		}
+		if !vectorsEqual(res.Solution, expected.Solution) {
+			t.Errorf("expected solution %v but got %v", expected.Solution, res.Solution)
+		}
+
+		infeasible := Vector{4, -1, 9}
+		if !tableau.SetConstraintVector(infeasible) {
+			t.Fatal("failed to set constraint vector")
+		}
+		res = DualSimplex(tableau, rule)
+		problem.ConstraintVector = infeasible
+		if res.Status != Infeasible {
+			t.Fatalf("unexpected status: %v", res.Status)
+		}
+		if !VerifyFarkas(problem, res.Farkas) {
+			t.Errorf("invalid certificate: %v", res.Farkas)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package linprog
+
+// Duals computes the dual vector y for a phase 2 tableau.
+//
+// The entries of y correspond to the rows of the original
+// program's constraint matrix A, regardless of which rows
+// were negated to create the phase 1 tableau.
+// At an optimal basis, y is the vector of shadow prices:
+// y[i] is the rate at which the objective grows as b[i]
+// increases.
+// Rows which were dropped as redundant get a dual of 0.
+//
+// If the tableau was not produced by phase 1, or if the
+// basis is numerically singular, nil is returned.
+func (s *SimplexTableau) Duals() Vector {
+	lu := s.basisLU()
+	if lu == nil {
+		return nil
+	}
+	return s.duals(lu)
+}
+
+// duals is like Duals, but it reuses a factorization from
+// basisLU.
+func (s *SimplexTableau) duals(lu *LU) Vector {
+	basicCosts := make(Vector, len(s.lp.ConstraintVector))
+	for row, basic := range s.RowToBasic {
+		basicCosts[row] = s.lp.Objective[basic]
+	}
+	return lu.SolveTranspose(basicCosts)
+}
+
+// ReducedCosts computes the reduced cost c_j - y'*A_j of
+// every variable in the original program, where y is the
+// dual vector from Duals.
+//
+// At an optimal basis, the reduced costs of basic
+// variables are zero, and every other reduced cost is at
+// most zero, except for variables at their upper bounds,
+// whose reduced costs are at least zero.
+func (s *SimplexTableau) ReducedCosts() Vector {
+	res := s.Costs()
+	for variable := range s.complemented {
+		res[variable] *= -1
+	}
+	for basic := range s.BasicToRow {
+		res[basic] = 0
+	}
+	return res
+}
+
+// basisLU factorizes the basis matrix of a phase 2
+// tableau.
+//
+// Each column of the basis matrix is the column of A for
+// the corresponding row's basic variable.
+// For redundant rows, which have no basic variable, a
+// unit column is used instead. This keeps the basis
+// matrix non-singular, since the row was redundant with
+// respect to the other rows.
+func (s *SimplexTableau) basisLU() *LU {
+	if s.lp == nil || s.Dim() != s.lp.Dim() {
+		return nil
+	}
+	numRows := len(s.lp.ConstraintVector)
+	basis := NewDenseMatrix(numRows, numRows)
+	for row := 0; row < numRows; row++ {
+		basic, ok := s.RowToBasic[row]
+		if !ok {
+			basis.Set(row, row, 1)
+			continue
+		}
+		for i, x := range s.lp.ConstraintMatrix.CopyCol(basic) {
+			basis.Set(i, row, x)
+		}
+	}
+	return NewLU(basis)
+}
@@ -0,0 +1,86 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDuals(t *testing.T) {
+	problems := map[string]*StandardLP{
+		"Flipped": {
+			// Maximize x + 2y subject to -x - y = -4,
+			// x - y + s = 2.
+			Objective: Vector{1, 2, 0},
+			ConstraintMatrix: &DenseMatrix{
+				NumRows: 2,
+				NumCols: 3,
+				Data:    []float64{-1, -1, 0, 1, -1, 1},
+			},
+			ConstraintVector: Vector{-4, 2},
+		},
+		"Redundant": {
+			// Maximize -4.5x + 3.5y - z subject to
+			// x - y + z = 1, 2x - 2y + 2z = 2, x + z = 3.
+			Objective: Vector{-4.5, 3.5, -1},
+			ConstraintMatrix: &DenseMatrix{
+				NumRows: 3,
+				NumCols: 3,
+				Data:    []float64{1, -1, 1, 2, -2, 2, 1, 0, 1},
+			},
+			ConstraintVector: Vector{1, 2, 3},
+		},
+	}
+	for name, problem := range problems {
+		t.Run(name, func(t *testing.T) {
+			res := Simplex(problem, BlandPivotRule{}, false)
+			if res.Status != Optimal {
+				t.Fatalf("unexpected status: %v", res.Status)
+			}
+			if math.Abs(res.Duals.Dot(problem.ConstraintVector)-res.Objective) > 1e-5 {
+				t.Errorf("duality gap: dual objective %f, primal objective %f",
+					res.Duals.Dot(problem.ConstraintVector), res.Objective)
+			}
+			expected := append(Vector{}, problem.Objective...)
+			for i, y := range res.Duals {
+				expected.Add(problem.ConstraintMatrix.CopyRow(i), -y)
+			}
+			if !vectorsEqual(expected, res.ReducedCosts) {
+				t.Errorf("expected reduced costs %v but got %v", expected, res.ReducedCosts)
+			}
+			if maxEntry(res.ReducedCosts) > 1e-5 {
+				t.Errorf("reduced costs are not optimal: %v", res.ReducedCosts)
+			}
+		})
+	}
+}
+
+func TestModelDuals(t *testing.T) {
+	m := NewModel()
+	x := m.AddNonNegVar("x")
+	y := m.AddNonNegVar("y")
+	m.Minimize(Sum(x.Mul(-3), y.Mul(-5)))
+	m.AddConstraint("plant1", x.Expr(), LessEqual, 4)
+	m.AddConstraint("plant2", y.Mul(2), LessEqual, 12)
+	m.AddConstraint("plant3", Sum(x.Mul(3), y.Mul(2)), LessEqual, 18)
+	solution := m.Solve(GreedyPivotRule{}, true)
+	if solution.Status() != Optimal {
+		t.Fatalf("unexpected status: %v", solution.Status())
+	}
+	for name, expected := range map[string]float64{"plant1": 0, "plant2": -1.5,
+		"plant3": -1} {
+		if actual := solution.Dual(name); math.Abs(actual-expected) > 1e-5 {
+			t.Errorf("constraint %s: expected dual %f but got %f", name, expected, actual)
+		}
+	}
+	if math.Abs(solution.ReducedCost(x))+math.Abs(solution.ReducedCost(y)) > 1e-5 {
+		t.Errorf("unexpected reduced costs: %v", solution.ReducedCosts)
+	}
+}
+
+func maxEntry(v Vector) float64 {
+	res := math.Inf(-1)
+	for _, x := range v {
+		res = math.Max(res, x)
+	}
+	return res
+}
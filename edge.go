@@ -0,0 +1,154 @@
+package linprog
+
+import "math"
+
+// devexResetThreshold is the reference weight above which
+// DevexPivotRule resets its reference framework.
+const devexResetThreshold = 1e6
+
+// SteepestEdgePivotRule is a PivotRule that picks the
+// column whose edge of the feasible polytope improves the
+// objective the most per unit of distance.
+//
+// The entering variable maximizes d_j^2/w_j, where d_j is
+// the relative cost coefficient and w_j = 1 + |T_j|^2 is
+// the squared norm of the edge direction, with T_j being
+// the variable's tableau column.
+//
+// The weights are kept across pivots, and only the columns
+// which a pivot changes are recomputed.
+// A zero SteepestEdgePivotRule is ready to use, but it
+// must be used through a pointer so that it can observe
+// pivots.
+type SteepestEdgePivotRule struct {
+	// RatioTest chooses the leaving variable.
+	// If it is nil, StandardRatioTest is used.
+	RatioTest RatioTest
+
+	tableau *SimplexTableau
+	weights Vector
+	stale   []bool
+}
+
+func (s *SteepestEdgePivotRule) ChoosePivot(t *SimplexTableau) (int, int, SimplexStatus) {
+	s.sync(t)
+	return weightedPivot(t, s.weights, s.RatioTest)
+}
+
+// ObservePivot marks the columns changed by a pivot so
+// that their weights are recomputed.
+func (s *SteepestEdgePivotRule) ObservePivot(t *SimplexTableau, leaving, entering int) {
+	s.sync(t)
+	if leaving == entering {
+		// A bound flip negates a column, which does not
+		// change its norm.
+		return
+	}
+	pivotRow := t.Matrix.CopyRow(t.BasicToRow[leaving])
+	for i, x := range pivotRow[:t.Dim()] {
+		if x != 0 {
+			s.stale[i] = true
+		}
+	}
+	s.stale[leaving] = true
+}
+
+// sync resets the weights if the tableau is new, and then
+// recomputes any stale weights of non-basic variables.
+func (s *SteepestEdgePivotRule) sync(t *SimplexTableau) {
+	if s.tableau != t || len(s.weights) != t.Dim() {
+		s.tableau = t
+		s.weights = make(Vector, t.Dim())
+		s.stale = make([]bool, t.Dim())
+		for i := range s.stale {
+			s.stale[i] = true
+		}
+	}
+	numRows := t.Matrix.Rows() - 1
+	for i, stale := range s.stale {
+		if stale && !t.Basic(i) {
+			column := t.Matrix.CopyCol(i)[:numRows]
+			s.weights[i] = 1 + column.Dot(column)
+			s.stale[i] = false
+		}
+	}
+}
+
+// DevexPivotRule is a PivotRule that approximates
+// SteepestEdgePivotRule using Devex reference weights.
+//
+// The weights start at 1 and are updated after each pivot
+// using only the pivot row, so they are much cheaper to
+// maintain than exact edge norms.
+// When the weights grow too large, the reference
+// framework is reset.
+//
+// A zero DevexPivotRule is ready to use, but it must be
+// used through a pointer so that it can observe pivots.
+type DevexPivotRule struct {
+	// RatioTest chooses the leaving variable.
+	// If it is nil, StandardRatioTest is used.
+	RatioTest RatioTest
+
+	tableau *SimplexTableau
+	weights Vector
+}
+
+func (d *DevexPivotRule) ChoosePivot(t *SimplexTableau) (int, int, SimplexStatus) {
+	d.sync(t)
+	return weightedPivot(t, d.weights, d.RatioTest)
+}
+
+// ObservePivot updates the reference weights for a pivot.
+func (d *DevexPivotRule) ObservePivot(t *SimplexTableau, leaving, entering int) {
+	d.sync(t)
+	if leaving == entering {
+		return
+	}
+	pivotRow := t.Matrix.CopyRow(t.BasicToRow[leaving])
+	pivot := pivotRow[entering]
+	enteringWeight := d.weights[entering]
+	var maxWeight float64
+	for i, x := range pivotRow[:t.Dim()] {
+		if x != 0 && i != entering && !t.Basic(i) {
+			ratio := x / pivot
+			d.weights[i] = math.Max(d.weights[i], ratio*ratio*enteringWeight)
+			maxWeight = math.Max(maxWeight, d.weights[i])
+		}
+	}
+	d.weights[leaving] = math.Max(enteringWeight/(pivot*pivot), 1)
+	if math.Max(maxWeight, d.weights[leaving]) > devexResetThreshold {
+		d.reset()
+	}
+}
+
+func (d *DevexPivotRule) sync(t *SimplexTableau) {
+	if d.tableau != t || len(d.weights) != t.Dim() {
+		d.tableau = t
+		d.weights = make(Vector, t.Dim())
+		d.reset()
+	}
+}
+
+func (d *DevexPivotRule) reset() {
+	for i := range d.weights {
+		d.weights[i] = 1
+	}
+}
+
+// weightedPivot picks the entering variable which
+// maximizes the squared relative cost divided by its
+// weight, and then performs the ratio test.
+func weightedPivot(t *SimplexTableau, weights Vector, rt RatioTest) (int, int, SimplexStatus) {
+	enterVar := -1
+	bestScore := 0.0
+	for i, cost := range t.Costs() {
+		if !t.Basic(i) && cost > 0 {
+			if score := cost * cost / weights[i]; score > bestScore {
+				enterVar = i
+				bestScore = score
+			}
+		}
+	}
+	return finishPivot(t, enterVar, rt)
+}
@@ -0,0 +1,60 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedPivotRules(t *testing.T) {
+	var greedyIters, steepestIters int
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(30, 60)
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		greedyIters += expected.Phase1Iterations + expected.Phase2Iterations
+		for _, rule := range []PivotRule{&SteepestEdgePivotRule{}, &DevexPivotRule{}} {
+			actual := Simplex(problem, rule, true)
+			if actual.Status != expected.Status {
+				t.Fatalf("%T: expected status %v but got %v", rule, expected.Status,
+					actual.Status)
+			}
+			if expected.Status == Optimal &&
+				math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+				t.Errorf("%T: expected objective %f but got %f", rule, expected.Objective,
+					actual.Objective)
+			}
+			if _, ok := rule.(*SteepestEdgePivotRule); ok {
+				steepestIters += actual.Phase1Iterations + actual.Phase2Iterations
+			}
+		}
+	}
+	if steepestIters > greedyIters {
+		t.Errorf("steepest edge took %d iterations but greedy took %d", steepestIters,
+			greedyIters)
+	}
+}
+
+func TestSteepestEdgeWeights(t *testing.T) {
+	problem := randomStandardLP(10, 20)
+	rule := &SteepestEdgePivotRule{}
+	tableau := SimplexPhase1(problem, rule, true)
+	if tableau == nil {
+		t.Fatal("phase 1 failed")
+	}
+	for {
+		leaving, entering, status := rule.ChoosePivot(tableau)
+		for i, weight := range rule.weights {
+			if tableau.Basic(i) {
+				continue
+			}
+			column := tableau.Matrix.CopyCol(i)[:tableau.Matrix.Rows()-1]
+			if expected := 1 + column.Dot(column); math.Abs(weight-expected) > 1e-8*expected {
+				t.Fatalf("variable %d: expected weight %f but got %f", i, expected, weight)
+			}
+		}
+		if status != Working {
+			break
+		}
+		rule.ObservePivot(tableau, leaving, entering)
+		tableau.Pivot(leaving, entering)
+	}
+}
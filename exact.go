@@ -0,0 +1,365 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// An ExactResult is the outcome of solving a linear
+// program in exact rational arithmetic.
+//
+// The fields are like those of Result, but the values are
+// exact, so the solution satisfies the constraints
+// exactly and the certificates can be checked exactly.
+type ExactResult struct {
+	Status SimplexStatus
+
+	// Solution is the final primal solution.
+	// It is nil unless Status is Optimal or Unbounded.
+	Solution  RatVector
+	Objective *big.Rat
+
+	Phase1Iterations int
+	Phase2Iterations int
+
+	// Basis stores the basic variable for each constraint
+	// row, or -1 for rows that were dropped as redundant.
+	// For programs with upper bounds, it is the basis of
+	// the program produced by BoundsToRows.
+	Basis []int
+
+	// Duals is the dual vector at the optimal basis.
+	// It is nil unless Status is Optimal.
+	Duals RatVector
+
+	// Ray is a direction of unbounded improvement.
+	// It is nil unless Status is Unbounded.
+	Ray RatVector
+
+	// Farkas is a certificate of infeasibility, scaled so
+	// that y'*b = 1.
+	// It is nil unless Status is Infeasible, and it is
+	// also nil for programs with upper bounds.
+	Farkas RatVector
+}
+
+// ExactSimplex solves a linear program with the simplex
+// method, using exact rational arithmetic throughout.
+//
+// The entries of the program are converted to rationals
+// exactly, so the result is the exact answer for the
+// program as given, free of rounding error.
+// Bland's rule is used for both the entering and leaving
+// variables, so the method cannot cycle.
+//
+// Rational arithmetic is much slower than floating point
+// arithmetic, so this is best suited to small and medium
+// programs, and to checking other algorithms with
+// VerifyExact.
+func ExactSimplex(lp *StandardLP) *ExactResult {
+	system := lp.BoundsToRows()
+	res := &ExactResult{}
+	t := NewRatTableauPhase1(system)
+
+	// Phase 1 is bounded, so it always ends at an optimum.
+	t.run(&res.Phase1Iterations)
+	if t.ObjectiveValue().Sign() < 0 {
+		res.Status = Infeasible
+		if system == lp {
+			res.Farkas = t.farkas(lp)
+		}
+		return res
+	}
+
+	t.phase1ToPhase2(system)
+	status, entering := t.run(&res.Phase2Iterations)
+	res.Status = status
+	res.Basis = t.Basis()
+	switch status {
+	case Optimal:
+		res.Solution = t.Solution()[:lp.Dim()]
+		res.Objective = t.ObjectiveValue()
+		res.Duals = t.duals(system)[:len(lp.ConstraintVector)]
+	case Unbounded:
+		res.Solution = t.Solution()[:lp.Dim()]
+		res.Objective = t.ObjectiveValue()
+		res.Ray = t.Ray(entering)[:lp.Dim()]
+	}
+	return res
+}
+
+// VerifyExact checks a floating-point result against an
+// exact solution of the same program.
+//
+// It returns an error if the statuses differ, or if the
+// objective values of optimal results differ by more than
+// a relative tolerance.
+func VerifyExact(lp *StandardLP, res *Result, tolerance float64) error {
+	exact := ExactSimplex(lp)
+	if exact.Status != res.Status {
+		return fmt.Errorf("expected status %v but got %v", exact.Status, res.Status)
+	}
+	if exact.Status == Optimal {
+		expected, _ := exact.Objective.Float64()
+		if math.Abs(res.Objective-expected) > tolerance*(1+math.Abs(expected)) {
+			return fmt.Errorf("expected objective %v but got %v", exact.Objective, res.Objective)
+		}
+	}
+	return nil
+}
+
+// A RatTableau is like a SimplexTableau, but it stores
+// exact rational numbers.
+//
+// Unlike SimplexTableau, the artificial variables stay in
+// the tableau during phase 2, since their columns reveal
+// the dual vector. They are never chosen to enter the
+// basis in phase 2.
+type RatTableau struct {
+	Matrix *RatMatrix
+
+	// RowToBasic and BasicToRow are like the fields of
+	// SimplexTableau.
+	RowToBasic map[int]int
+	BasicToRow map[int]int
+
+	// numEligible is the number of leading variables
+	// which may enter the basis.
+	numEligible int
+}
+
+// NewRatTableauPhase1 creates a RatTableau for phase 1 of
+// the simplex method, like NewTableauPhase1.
+//
+// Upper bounds are ignored; see BoundsToRows.
+func NewRatTableauPhase1(lp *StandardLP) *RatTableau {
+	numRows := len(lp.ConstraintVector)
+	numCols := lp.Dim() + numRows + 1
+	matrix := NewRatMatrix(numRows+1, numCols)
+	for row := 0; row < numRows; row++ {
+		for j, x := range lp.ConstraintMatrix.CopyRow(row) {
+			if x != 0 {
+				matrix.At(row, j).SetFloat64(x)
+			}
+		}
+		matrix.At(row, lp.Dim()+row).SetInt64(1)
+		matrix.At(row, numCols-1).SetFloat64(lp.ConstraintVector[row])
+		if lp.ConstraintVector[row] < 0 {
+			matrix.ScaleRow(row, big.NewRat(-1, 1))
+			matrix.At(row, lp.Dim()+row).SetInt64(1)
+		}
+	}
+
+	// Set up the relative cost coefficients of phase 1, in
+	// which the artificial variables have a cost of -1.
+	one := big.NewRat(1, 1)
+	for row := 0; row < numRows; row++ {
+		matrix.At(numRows, lp.Dim()+row).SetInt64(-1)
+		matrix.AddRow(row, numRows, one)
+	}
+
+	res := &RatTableau{
+		Matrix:      matrix,
+		RowToBasic:  map[int]int{},
+		BasicToRow:  map[int]int{},
+		numEligible: numCols - 1,
+	}
+	for row := 0; row < numRows; row++ {
+		res.RowToBasic[row] = lp.Dim() + row
+		res.BasicToRow[lp.Dim()+row] = row
+	}
+	return res
+}
+
+// Dim returns the number of variables, including the
+// artificial variables.
+func (r *RatTableau) Dim() int {
+	return r.Matrix.Cols() - 1
+}
+
+// Pivot takes two variables, one which is basic and one
+// which is not, and swaps their roles.
+func (r *RatTableau) Pivot(leaving, entering int) {
+	row := r.BasicToRow[leaving]
+	r.Matrix.ScaleRow(row, new(big.Rat).Inv(r.Matrix.At(row, entering)))
+	for i := 0; i < r.Matrix.Rows(); i++ {
+		if entry := r.Matrix.At(i, entering); i != row && entry.Sign() != 0 {
+			r.Matrix.AddRow(row, i, new(big.Rat).Neg(entry))
+		}
+	}
+	r.RowToBasic[row] = entering
+	delete(r.BasicToRow, leaving)
+	r.BasicToRow[entering] = row
+}
+
+// ObjectiveValue gets the current value of the objective.
+func (r *RatTableau) ObjectiveValue() *big.Rat {
+	return new(big.Rat).Neg(r.Matrix.At(r.Matrix.Rows()-1, r.Dim()))
+}
+
+// Cost gets the relative cost coefficient for a variable.
+// The result should not be modified.
+func (r *RatTableau) Cost(i int) *big.Rat {
+	return r.Matrix.At(r.Matrix.Rows()-1, i)
+}
+
+// Basic checks if a variable is basic.
+func (r *RatTableau) Basic(i int) bool {
+	_, res := r.BasicToRow[i]
+	return res
+}
+
+// Basis is like SimplexTableau.Basis.
+func (r *RatTableau) Basis() []int {
+	res := make([]int, r.Matrix.Rows()-1)
+	for row := range res {
+		if basic, ok := r.RowToBasic[row]; ok {
+			res[row] = basic
+		} else {
+			res[row] = -1
+		}
+	}
+	return res
+}
+
+// Solution gets the current solution vector, including
+// the artificial variables.
+func (r *RatTableau) Solution() RatVector {
+	res := NewRatVectorZero(r.Dim())
+	for basic, row := range r.BasicToRow {
+		res[basic].Set(r.Matrix.At(row, r.Dim()))
+	}
+	return res
+}
+
+// Ray is like SimplexTableau.Ray.
+func (r *RatTableau) Ray(entering int) RatVector {
+	res := NewRatVectorZero(r.Dim())
+	res[entering].SetInt64(1)
+	for basic, row := range r.BasicToRow {
+		res[basic].Neg(r.Matrix.At(row, entering))
+	}
+	return res
+}
+
+// run pivots with Bland's rule until the tableau is
+// optimal or unbounded.
+// If it is unbounded, the entering variable is returned.
+func (r *RatTableau) run(iterations *int) (SimplexStatus, int) {
+	for {
+		entering := -1
+		for i := 0; i < r.numEligible; i++ {
+			if !r.Basic(i) && r.Cost(i).Sign() > 0 {
+				entering = i
+				break
+			}
+		}
+		if entering == -1 {
+			return Optimal, -1
+		}
+		leaving := r.leavingVariable(entering)
+		if leaving == -1 {
+			return Unbounded, entering
+		}
+		r.Pivot(leaving, entering)
+		*iterations++
+	}
+}
+
+// leavingVariable performs the ratio test, breaking ties
+// in favor of the lowest-indexed basic variable.
+func (r *RatTableau) leavingVariable(entering int) int {
+	leaving := -1
+	var minRatio, ratio big.Rat
+	for row, basic := range r.RowToBasic {
+		entry := r.Matrix.At(row, entering)
+		if entry.Sign() <= 0 {
+			continue
+		}
+		ratio.Quo(r.Matrix.At(row, r.Dim()), entry)
+		if leaving == -1 {
+			minRatio.Set(&ratio)
+			leaving = basic
+		} else if cmp := ratio.Cmp(&minRatio); cmp < 0 || (cmp == 0 && basic < leaving) {
+			minRatio.Set(&ratio)
+			leaving = basic
+		}
+	}
+	return leaving
+}
+
+// phase1ToPhase2 drives the artificial variables out of
+// the basis, drops redundant rows, and sets up the costs
+// for the true objective.
+//
+// It assumes that phase 1 reached an objective of zero,
+// so every artificial variable is zero.
+func (r *RatTableau) phase1ToPhase2(lp *StandardLP) {
+	for row := 0; row < r.Matrix.Rows()-1; row++ {
+		artificial := r.RowToBasic[row]
+		if artificial < lp.Dim() {
+			continue
+		}
+		found := false
+		for j := 0; j < lp.Dim(); j++ {
+			if !r.Basic(j) && r.Matrix.At(row, j).Sign() != 0 {
+				r.Pivot(artificial, j)
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.Matrix.ScaleRow(row, new(big.Rat))
+			delete(r.RowToBasic, row)
+			delete(r.BasicToRow, artificial)
+		}
+	}
+
+	costRow := r.Matrix.Row(r.Matrix.Rows() - 1)
+	for i, x := range costRow {
+		if i < lp.Dim() {
+			x.SetFloat64(lp.Objective[i])
+		} else {
+			x.SetInt64(0)
+		}
+	}
+	for basic, row := range r.BasicToRow {
+		r.Matrix.AddRow(row, r.Matrix.Rows()-1, new(big.Rat).Neg(r.Cost(basic)))
+	}
+	r.numEligible = lp.Dim()
+}
+
+// duals computes the dual vector from the costs of the
+// artificial variables, which are -w for the dual vector
+// w of the sign-adjusted rows.
+func (r *RatTableau) duals(lp *StandardLP) RatVector {
+	res := NewRatVectorZero(len(lp.ConstraintVector))
+	for i, b := range lp.ConstraintVector {
+		res[i].Set(r.Cost(lp.Dim() + i))
+		if b >= 0 {
+			res[i].Neg(res[i])
+		}
+	}
+	return res
+}
+
+// farkas computes a certificate of infeasibility from an
+// optimal phase 1 tableau, like FarkasCertificate.
+func (r *RatTableau) farkas(lp *StandardLP) RatVector {
+	res := NewRatVectorZero(len(lp.ConstraintVector))
+	one := big.NewRat(1, 1)
+	for i, b := range lp.ConstraintVector {
+		res[i].Add(r.Cost(lp.Dim()+i), one)
+		if b < 0 {
+			res[i].Neg(res[i])
+		}
+	}
+	if dot := res.Dot(NewRatVector(lp.ConstraintVector)); dot.Sign() > 0 {
+		dot.Inv(dot)
+		for _, x := range res {
+			x.Mul(x, dot)
+		}
+	}
+	return res
+}
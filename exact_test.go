@@ -0,0 +1,106 @@
+package linprog
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestExactSimplex(t *testing.T) {
+	problem := &StandardLP{
+		Objective: Vector{3, 5, 0, 0, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 3,
+			NumCols: 5,
+			Data: []float64{
+				1, 0, 1, 0, 0,
+				0, 2, 0, 1, 0,
+				3, 2, 0, 0, 1,
+			},
+		},
+		ConstraintVector: Vector{4, 12, 18},
+	}
+	res := ExactSimplex(problem)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	if res.Objective.Cmp(big.NewRat(36, 1)) != 0 {
+		t.Errorf("unexpected objective: %v", res.Objective)
+	}
+	expectedSolution := []*big.Rat{big.NewRat(2, 1), big.NewRat(6, 1), big.NewRat(2, 1),
+		new(big.Rat), new(big.Rat)}
+	if !ratVectorsEqual(res.Solution, expectedSolution) {
+		t.Errorf("unexpected solution: %v", res.Solution)
+	}
+	expectedDuals := []*big.Rat{new(big.Rat), big.NewRat(3, 2), big.NewRat(1, 1)}
+	if !ratVectorsEqual(res.Duals, expectedDuals) {
+		t.Errorf("unexpected duals: %v", res.Duals)
+	}
+}
+
+func TestExactSimplexCertificates(t *testing.T) {
+	problem := &StandardLP{
+		Objective: Vector{4.5, 3.5},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 2,
+			Data:    []float64{1, -1},
+		},
+		ConstraintVector: Vector{1},
+	}
+	res := ExactSimplex(problem)
+	if res.Status != Unbounded {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	matrix := NewRatMatrixFloat(problem.ConstraintMatrix)
+	if matrix.Row(0).Dot(res.Ray).Sign() != 0 ||
+		NewRatVector(problem.Objective).Dot(res.Ray).Sign() <= 0 {
+		t.Errorf("invalid ray: %v", res.Ray)
+	}
+
+	problem.ConstraintMatrix = &DenseMatrix{
+		NumRows: 2,
+		NumCols: 2,
+		Data:    []float64{1, -1, -2, 2},
+	}
+	problem.ConstraintVector = Vector{1, -1.5}
+	res = ExactSimplex(problem)
+	if res.Status != Infeasible {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	matrix = NewRatMatrixFloat(problem.ConstraintMatrix)
+	for col := 0; col < matrix.Cols(); col++ {
+		if matrix.CopyCol(col).Dot(res.Farkas).Sign() > 0 {
+			t.Errorf("invalid certificate: %v", res.Farkas)
+		}
+	}
+	if NewRatVector(problem.ConstraintVector).Dot(res.Farkas).Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("invalid certificate: %v", res.Farkas)
+	}
+}
+
+func TestVerifyExact(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		problem := randomStandardLP(8, 16)
+		if i%2 == 1 {
+			problem.UpperBounds = NewVectorRandom(problem.Dim()).Abs()
+		}
+		for _, rule := range []PivotRule{BlandPivotRule{}, GreedyPivotRule{}} {
+			res := Simplex(problem, rule, true)
+			if err := VerifyExact(problem, res, 1e-8); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+func ratVectorsEqual(v1, v2 RatVector) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+	for i, x := range v1 {
+		if x.Cmp(v2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,3 +1,5 @@
+import json
+
 import numpy as np
 import torch
 import torch.nn as nn
@@ -66,3 +68,26 @@ def input_linear_program(model, inputs, out_loss_fn, epsilon=0.1, min_val=0, max
     torch.sum(out_loss_fn(model(in_with_grad)[0])).backward()
     c = in_with_grad.grad.data.detach().cpu().numpy()
     return c.flatten(), np.array(A_ub), np.array(b_ub)
+
+
+def linear_program_json(c, A_ub, b_ub):
+    """
+    Encode a linear program from input_linear_program() in
+    the JSON format read by the Go linprog.Model type.
+
+    The program maximizes c'*x subject to A_ub*x <= b_ub,
+    with every variable unbounded.
+    """
+    rows, cols = np.nonzero(A_ub)
+    return json.dumps({
+        'sense': 'max',
+        'objective': [float(x) for x in c],
+        'matrix': {
+            'rows': [int(x) for x in rows],
+            'cols': [int(x) for x in cols],
+            'values': [float(x) for x in A_ub[rows, cols]],
+        },
+        'rhs': [float(x) for x in b_ub],
+        'relations': ['<='] * len(b_ub),
+        'lower_bounds': [None] * len(c),
+    })
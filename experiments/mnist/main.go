@@ -48,13 +48,15 @@ func GenerateAdversarial(classifier anynet.Net, sample mnist.Sample) []float64 {
 	gradVec.Scale(-1)
 	activationsVec := linprog.Vector(Creator.Float64Slice(activations.Output().Data()))
 	weights, biases := ConvertLayer(classifier[0].(*anynet.FC))
-	system := CreateLinearProgram(sample.Intensities, gradVec, activationsVec, biases, weights)
+	system, offsets := CreateLinearProgram(sample.Intensities, gradVec, activationsVec,
+		biases, weights)
 	log.Println("Solving linear program...")
-	solution, solved := linprog.Simplex(system, linprog.GreedyPivotRule{}, true)
-	if !solved {
-		essentials.Die("unsolvable system")
+	result := linprog.Simplex(system, linprog.GreedyPivotRule{}, true)
+	if result.Status != linprog.Optimal {
+		essentials.Die("unsolvable system:", result.Status)
 	}
-	solution = solution[:28*28]
+	solution := result.Solution[:28*28]
+	solution.Add(offsets, 1)
 
 	outs = classifier.Apply(anydiff.NewConst(anyvec.Make(Creator, solution)), 1)
 	newProb := math.Exp(Creator.Float64Slice(outs.Output().Data())[sample.Label])
@@ -73,51 +75,45 @@ func ConvertLayer(layer *anynet.FC) (linprog.Matrix, linprog.Vector) {
 	}, linprog.Vector(Creator.Float64Slice(layer.Biases.Vector.Data()))
 }
 
+// CreateLinearProgram creates a linear program over the
+// change in each input, which is kept within MaxDelta of
+// the original input using upper bounds.
+//
+// It returns the program and the minimum value of each
+// input, which must be added to the solution.
 func CreateLinearProgram(inputs, gradient, activations, biases linprog.Vector,
-	weights linprog.Matrix) *linprog.StandardLP {
-	numVars := len(gradient)
-	constraintRows := []linprog.Vector{}
-	constraintValues := linprog.Vector{}
+	weights linprog.Matrix) (*linprog.StandardLP, linprog.Vector) {
+	numInputs := len(gradient)
+	numVars := numInputs + len(activations)
+	offsets := make(linprog.Vector, numInputs)
+	upperBounds := make(linprog.Vector, numVars)
 	for i, x := range inputs {
-		if x > MaxDelta {
-			row := make(linprog.Vector, numVars+1)
-			row[i] = 1
-			row[numVars] = -1
-			numVars++
-			constraintRows = append(constraintRows, row)
-			constraintValues = append(constraintValues, x-MaxDelta)
-		}
-		maximum := math.Min(1, x+MaxDelta)
-		row := make(linprog.Vector, numVars+1)
-		row[i] = 1
-		row[numVars] = 1
-		numVars++
-		constraintRows = append(constraintRows, row)
-		constraintValues = append(constraintValues, maximum)
+		offsets[i] = math.Max(0, x-MaxDelta)
+		upperBounds[i] = math.Min(1, x+MaxDelta) - offsets[i]
+	}
+	for i := numInputs; i < numVars; i++ {
+		upperBounds[i] = math.Inf(1)
 	}
+
+	matrix := linprog.NewDenseMatrix(len(activations), numVars)
+	constraintValues := make(linprog.Vector, len(activations))
 	for i, activation := range activations {
-		row := make(linprog.Vector, numVars+1)
-		copy(row, weights.CopyRow(i))
+		row := weights.CopyRow(i)
+		copy(matrix.Row(i), row)
 		if activation < 0 {
-			row[numVars] = 1
+			matrix.Set(i, numInputs+i, 1)
 		} else {
-			row[numVars] = -1
+			matrix.Set(i, numInputs+i, -1)
 		}
-		numVars++
-		constraintValues = append(constraintValues, -biases[i])
-		constraintRows = append(constraintRows, row)
-	}
-
-	matrix := linprog.NewDenseMatrix(len(constraintRows), numVars)
-	for i, row := range constraintRows {
-		copy(matrix.Data[i*matrix.NumCols:(i+1)*matrix.NumCols], row)
+		constraintValues[i] = -biases[i] - row.Dot(offsets)
 	}
 
 	return &linprog.StandardLP{
-		Objective:        append(gradient, make([]float64, numVars-len(gradient))...),
+		Objective:        append(gradient, make([]float64, len(activations))...),
 		ConstraintMatrix: matrix,
 		ConstraintVector: constraintValues,
-	}
+		UpperBounds:      upperBounds,
+	}, offsets
 }
 
 func SaveImage(name string, intensities []float64) {
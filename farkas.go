@@ -0,0 +1,66 @@
+package linprog
+
+// FarkasCertificate extracts a certificate of
+// infeasibility from an optimal phase 1 tableau whose
+// objective is negative.
+//
+// The certificate is a vector y such that y'*A <= 0 and
+// y'*b > 0, where A and b come from the original program.
+// No non-negative x can satisfy A*x = b, since then
+// y'*b = y'*A*x <= 0.
+// The certificate is scaled so that y'*b = 1.
+//
+// If the tableau is not a phase 1 tableau, nil is
+// returned. Likewise, nil is returned if the program has
+// upper bounds, since they may be needed to prove
+// infeasibility.
+func (s *SimplexTableau) FarkasCertificate() Vector {
+	if s.lp == nil || s.lp.hasUpperBounds() {
+		return nil
+	}
+	numRows := len(s.lp.ConstraintVector)
+	if s.Dim() != s.lp.Dim()+numRows {
+		return nil
+	}
+
+	// The cost row is [0 -1 0] + w'*[S*A I S*b], where S
+	// negates the rows with negative b values, so the
+	// costs of the artificial variables reveal w.
+	res := make(Vector, numRows)
+	for i := range res {
+		res[i] = s.Cost(s.lp.Dim()+i) + 1
+		if s.lp.ConstraintVector[i] < 0 {
+			res[i] *= -1
+		}
+	}
+	if dot := res.Dot(s.lp.ConstraintVector); dot > 0 {
+		res.Scale(1 / dot)
+	}
+	return res
+}
+
+// VerifyFarkas checks if y is a certificate that lp has
+// no feasible solutions, meaning that y'*A <= 0 and
+// y'*b > 0.
+//
+// Small positive entries of y'*A are tolerated to account
+// for rounding error.
+func VerifyFarkas(lp *StandardLP, y Vector) bool {
+	if len(y) != len(lp.ConstraintVector) {
+		return false
+	}
+	epsilon := relativeEpsilon * y.AbsMax() * float64(len(y))
+	combination := make(Vector, lp.Dim())
+	for i, coeff := range y {
+		if coeff != 0 {
+			combination.Add(lp.ConstraintMatrix.CopyRow(i), coeff)
+		}
+	}
+	matrixEpsilon := epsilon * lp.ConstraintMatrix.AbsMax()
+	for _, x := range combination {
+		if x > matrixEpsilon {
+			return false
+		}
+	}
+	return y.Dot(lp.ConstraintVector) > epsilon*lp.ConstraintVector.AbsMax()
+}
@@ -0,0 +1,46 @@
+package linprog
+
+import "testing"
+
+func TestFarkasCertificate(t *testing.T) {
+	problems := map[string]*StandardLP{
+		"Inconsistent": {
+			// x - y = 1, 2x - 2y = 1.5.
+			Objective: Vector{4.5, 3.5},
+			ConstraintMatrix: &DenseMatrix{
+				NumRows: 2,
+				NumCols: 2,
+				Data:    []float64{1, -1, 2, -2},
+			},
+			ConstraintVector: Vector{1, 1.5},
+		},
+		"Negative": {
+			// x + y - z = 2, x + 2y = -1.
+			Objective: Vector{1, 1, 1},
+			ConstraintMatrix: &DenseMatrix{
+				NumRows: 2,
+				NumCols: 3,
+				Data:    []float64{1, 1, -1, 1, 2, 0},
+			},
+			ConstraintVector: Vector{2, -1},
+		},
+	}
+	for name, problem := range problems {
+		t.Run(name, func(t *testing.T) {
+			for _, dense := range []bool{false, true} {
+				res := Simplex(problem, GreedyPivotRule{}, dense)
+				if res.Status != Infeasible {
+					t.Fatalf("unexpected status: %v", res.Status)
+				}
+				if !VerifyFarkas(problem, res.Farkas) {
+					t.Errorf("invalid certificate: %v", res.Farkas)
+				}
+				negated := append(Vector{}, res.Farkas...)
+				negated.Scale(-1)
+				if VerifyFarkas(problem, negated) {
+					t.Errorf("negated certificate should be invalid: %v", negated)
+				}
+			}
+		})
+	}
+}
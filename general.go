@@ -0,0 +1,314 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+)
+
+// A ConstraintType is the relation between the two sides
+// of a constraint in a GeneralLP.
+type ConstraintType int
+
+const (
+	// Equal indicates a constraint a'*x = b.
+	Equal ConstraintType = iota
+
+	// LessEqual indicates a constraint a'*x <= b.
+	LessEqual
+
+	// GreaterEqual indicates a constraint a'*x >= b.
+	GreaterEqual
+)
+
+// A GeneralLP is a linear program in general form.
+// In particular, it takes the form:
+//
+//	maximize (or minimize) c'*x
+//	subject to A_i*x (=, <=, or >=) b_i for every row i
+//	           l <= x <= u
+//
+// Lower bounds may be -Inf and upper bounds may be +Inf.
+// A variable with both bounds infinite is free.
+type GeneralLP struct {
+	// Minimize indicates that the objective should be
+	// minimized rather than maximized.
+	Minimize bool
+
+	Objective        Vector
+	ConstraintMatrix Matrix
+	ConstraintVector Vector
+
+	// ConstraintTypes stores the relation for each row of
+	// the constraint matrix.
+	// If nil, every constraint is an equality.
+	ConstraintTypes []ConstraintType
+
+	// LowerBounds stores a lower bound for each variable.
+	// If nil, every variable is bounded below by 0.
+	LowerBounds Vector
+
+	// UpperBounds stores an upper bound for each variable.
+	// If nil, no variable is bounded above.
+	UpperBounds Vector
+}
+
+// Dim gets the number of variables in the program.
+func (g *GeneralLP) Dim() int {
+	return len(g.Objective)
+}
+
+// ConstraintType gets the relation for a constraint row.
+func (g *GeneralLP) ConstraintType(row int) ConstraintType {
+	if g.ConstraintTypes == nil {
+		return Equal
+	}
+	return g.ConstraintTypes[row]
+}
+
+// LowerBound gets the lower bound of a variable.
+func (g *GeneralLP) LowerBound(i int) float64 {
+	if g.LowerBounds == nil {
+		return 0
+	}
+	return g.LowerBounds[i]
+}
+
+// UpperBound gets the upper bound of a variable.
+func (g *GeneralLP) UpperBound(i int) float64 {
+	if g.UpperBounds == nil {
+		return math.Inf(1)
+	}
+	return g.UpperBounds[i]
+}
+
+// Validate checks that every variable has a non-empty
+// range of values, with a lower bound below +Inf and an
+// upper bound above -Inf.
+func (g *GeneralLP) Validate() error {
+	for i := 0; i < g.Dim(); i++ {
+		lower, upper := g.LowerBound(i), g.UpperBound(i)
+		if !(lower <= upper) || math.IsInf(lower, 1) || math.IsInf(upper, -1) {
+			return fmt.Errorf("variable %d has invalid bounds [%g, %g]", i, lower, upper)
+		}
+	}
+	return nil
+}
+
+// Standardize converts the program into an equivalent
+// StandardLP.
+//
+// Inequalities receive slack variables, and variables
+// are shifted and split so that they are non-negative.
+// Variables with two finite bounds keep the distance
+// between them as an upper bound in the StandardLP.
+// The returned mapping translates solutions back to the
+// original variable space.
+//
+// Standardize panics if Validate fails.
+func (g *GeneralLP) Standardize() (*StandardLP, *StandardMapping) {
+	if err := g.Validate(); err != nil {
+		panic(err)
+	}
+	mapping := &StandardMapping{
+		NumConstraints: len(g.ConstraintVector),
+		Columns:        make([]int, g.Dim()),
+		NegColumns:     make([]int, g.Dim()),
+		Signs:          make([]float64, g.Dim()),
+		Offsets:        make(Vector, g.Dim()),
+		Negated:        g.Minimize,
+	}
+
+	numCols := 0
+	var boundedVars []int
+	for i := 0; i < g.Dim(); i++ {
+		lower, upper := g.LowerBound(i), g.UpperBound(i)
+		mapping.Columns[i] = numCols
+		mapping.NegColumns[i] = -1
+		numCols++
+		if !math.IsInf(lower, -1) {
+			mapping.Signs[i] = 1
+			mapping.Offsets[i] = lower
+			if !math.IsInf(upper, 1) {
+				boundedVars = append(boundedVars, i)
+			}
+		} else if !math.IsInf(upper, 1) {
+			mapping.Signs[i] = -1
+			mapping.Offsets[i] = upper
+		} else {
+			mapping.Signs[i] = 1
+			mapping.NegColumns[i] = numCols
+			numCols++
+		}
+	}
+	var slackRows []int
+	for i := range g.ConstraintVector {
+		if g.ConstraintType(i) != Equal {
+			slackRows = append(slackRows, i)
+		}
+	}
+	numSlacks := len(slackRows)
+	numRows := len(g.ConstraintVector)
+
+	var matrix Matrix
+	if _, ok := g.ConstraintMatrix.(*SparseMatrix); ok {
+		matrix = NewSparseMatrix(numRows, numCols+numSlacks)
+	} else {
+		matrix = NewDenseMatrix(numRows, numCols+numSlacks)
+	}
+	vector := make(Vector, numRows)
+
+	for row := range g.ConstraintVector {
+		vector[row] = g.ConstraintVector[row]
+		for j, x := range g.ConstraintMatrix.CopyRow(row) {
+			if x == 0 {
+				continue
+			}
+			vector[row] -= x * mapping.Offsets[j]
+			matrix.Set(row, mapping.Columns[j], x*mapping.Signs[j])
+			if neg := mapping.NegColumns[j]; neg != -1 {
+				matrix.Set(row, neg, -x)
+			}
+		}
+	}
+	slackCol := numCols
+	for _, row := range slackRows {
+		if g.ConstraintType(row) == LessEqual {
+			matrix.Set(row, slackCol, 1)
+		} else {
+			matrix.Set(row, slackCol, -1)
+		}
+		slackCol++
+	}
+
+	var upperBounds Vector
+	if len(boundedVars) > 0 {
+		upperBounds = make(Vector, numCols+numSlacks)
+		for i := range upperBounds {
+			upperBounds[i] = math.Inf(1)
+		}
+		for _, variable := range boundedVars {
+			upperBounds[mapping.Columns[variable]] = g.UpperBound(variable) -
+				g.LowerBound(variable)
+		}
+	}
+
+	objective := make(Vector, numCols+numSlacks)
+	objScale := 1.0
+	if g.Minimize {
+		objScale = -1
+	}
+	for j, c := range g.Objective {
+		mapping.ObjectiveOffset += c * mapping.Offsets[j]
+		objective[mapping.Columns[j]] = objScale * c * mapping.Signs[j]
+		if neg := mapping.NegColumns[j]; neg != -1 {
+			objective[neg] = -objScale * c
+		}
+	}
+
+	return &StandardLP{
+		Objective:        objective,
+		ConstraintMatrix: matrix,
+		ConstraintVector: vector,
+		UpperBounds:      upperBounds,
+	}, mapping
+}
+
+// A StandardMapping records how a GeneralLP was converted
+// into a StandardLP.
+//
+// Each original variable x_i is expressed in terms of the
+// standard variables y as
+//
+//	x_i = Offsets[i] + Signs[i]*y[Columns[i]] - y[NegColumns[i]]
+//
+// where the last term is omitted if NegColumns[i] is -1.
+type StandardMapping struct {
+	// NumConstraints is the number of constraint rows in
+	// the original program, which are also the rows of the
+	// standard program.
+	NumConstraints int
+
+	Columns    []int
+	NegColumns []int
+	Signs      []float64
+	Offsets    Vector
+
+	// Negated is true if the original program was a
+	// minimization, in which case the standard objective
+	// is the negated original objective.
+	Negated bool
+
+	// ObjectiveOffset is the constant part of the original
+	// objective which was dropped from the standard one.
+	ObjectiveOffset float64
+}
+
+// Solution translates a solution of the standard program
+// into a solution of the original program.
+func (s *StandardMapping) Solution(standard Vector) Vector {
+	res := make(Vector, len(s.Columns))
+	for i, col := range s.Columns {
+		res[i] = s.Offsets[i] + s.Signs[i]*standard[col]
+		if neg := s.NegColumns[i]; neg != -1 {
+			res[i] -= standard[neg]
+		}
+	}
+	return res
+}
+
+// Ray translates a direction in the standard program,
+// such as an unbounded ray, into a direction for the
+// original variables.
+//
+// Unlike Solution, it leaves out the offsets, since a
+// direction is not anchored at a point.
+func (s *StandardMapping) Ray(standard Vector) Vector {
+	res := make(Vector, len(s.Columns))
+	for i, col := range s.Columns {
+		res[i] = s.Signs[i] * standard[col]
+		if neg := s.NegColumns[i]; neg != -1 {
+			res[i] -= standard[neg]
+		}
+	}
+	return res
+}
+
+// ObjectiveValue translates an objective value of the
+// standard program into the original program's objective
+// value.
+func (s *StandardMapping) ObjectiveValue(standard float64) float64 {
+	if s.Negated {
+		standard = -standard
+	}
+	return standard + s.ObjectiveOffset
+}
+
+// Duals translates the dual vector of the standard
+// program into dual values for the original constraints.
+//
+// As with StandardLP duals, each value is the rate at
+// which the original objective grows as the constraint's
+// right-hand side increases.
+func (s *StandardMapping) Duals(standard Vector) Vector {
+	res := append(Vector{}, standard[:s.NumConstraints]...)
+	if s.Negated {
+		res.Scale(-1)
+	}
+	return res
+}
+
+// ReducedCosts translates reduced costs of the standard
+// program into reduced costs for the original variables.
+//
+// Each value is the rate at which the original objective
+// changes as the variable increases.
+func (s *StandardMapping) ReducedCosts(standard Vector) Vector {
+	res := make(Vector, len(s.Columns))
+	for i, col := range s.Columns {
+		res[i] = s.Signs[i] * standard[col]
+		if s.Negated {
+			res[i] *= -1
+		}
+	}
+	return res
+}
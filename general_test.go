@@ -0,0 +1,67 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeneralLPStandardize(t *testing.T) {
+	// Minimize x + 2y + 0z subject to
+	//     x + y >= 3
+	//     x - y <= 1
+	//     z - y = -4
+	//     0.5 <= x <= 10, y <= 5, z free.
+	problem := &GeneralLP{
+		Minimize:  true,
+		Objective: Vector{1, 2, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 3,
+			NumCols: 3,
+			Data: []float64{
+				1, 1, 0,
+				1, -1, 0,
+				0, -1, 1,
+			},
+		},
+		ConstraintVector: Vector{3, 1, -4},
+		ConstraintTypes:  []ConstraintType{GreaterEqual, LessEqual, Equal},
+		LowerBounds:      Vector{0.5, math.Inf(-1), math.Inf(-1)},
+		UpperBounds:      Vector{10, 5, math.Inf(1)},
+	}
+	standard, mapping := problem.Standardize()
+	if len(standard.ConstraintVector) != 3 {
+		t.Errorf("unexpected number of rows: %d", len(standard.ConstraintVector))
+	}
+	if bound := standard.UpperBound(mapping.Columns[0]); bound != 9.5 {
+		t.Errorf("unexpected upper bound: %f", bound)
+	}
+	res := Simplex(standard, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	actual := mapping.Solution(res.Solution)
+	if !vectorsEqual(actual, Vector{2, 1, -3}) {
+		t.Errorf("unexpected solution: %v", actual)
+	}
+	objective := mapping.ObjectiveValue(res.Objective)
+	if math.Abs(objective-4) > 1e-5 {
+		t.Errorf("unexpected objective: %f", objective)
+	}
+}
+
+func TestGeneralLPValidate(t *testing.T) {
+	problem := &GeneralLP{
+		Objective:        Vector{1, 1},
+		ConstraintMatrix: &DenseMatrix{NumRows: 1, NumCols: 2, Data: []float64{1, 1}},
+		ConstraintVector: Vector{1},
+		LowerBounds:      Vector{2, math.Inf(-1)},
+		UpperBounds:      Vector{2, 3},
+	}
+	if err := problem.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	problem.LowerBounds[1] = 4
+	if problem.Validate() == nil {
+		t.Error("expected an error for a lower bound above an upper bound")
+	}
+}
@@ -0,0 +1,192 @@
+package linprog
+
+import "math"
+
+// An IIS is an irreducible infeasible subset of the
+// constraints of a StandardLP.
+//
+// The constraints in the subset cannot be satisfied
+// simultaneously, but removing any one of them makes the
+// remaining constraints satisfiable.
+type IIS struct {
+	// Rows stores the indices of constraint rows in the
+	// subset.
+	Rows []int
+
+	// Bounds stores the indices of variables whose
+	// non-negativity constraints are in the subset.
+	// Variables which are not listed are free.
+	Bounds []int
+
+	// UpperBounds stores the indices of variables whose
+	// upper bounds are in the subset.
+	UpperBounds []int
+}
+
+// FindIIS finds an irreducible infeasible subset of the
+// constraints of an infeasible program.
+//
+// The search starts from the constraints used by a Farkas
+// certificate, and then uses a deletion filter, removing
+// constraints one at a time as long as the rest remain
+// infeasible.
+// Each feasibility check runs the simplex method with the
+// given pivot rule.
+//
+// If lp is not found to be infeasible, nil is returned.
+func FindIIS(lp *StandardLP, pr PivotRule, dense bool) *IIS {
+	system := lp.BoundsToRows()
+	res := findIIS(system, pr, dense)
+	if res == nil || system == lp {
+		return res
+	}
+
+	// An upper bound is the row x_j + s_j = u_j together
+	// with s_j >= 0. Neither is needed without the other,
+	// so the row alone identifies the bound.
+	bounded := lp.boundedVariables()
+	numRows := len(lp.ConstraintVector)
+	mapped := &IIS{}
+	for _, row := range res.Rows {
+		if row < numRows {
+			mapped.Rows = append(mapped.Rows, row)
+		} else {
+			mapped.UpperBounds = append(mapped.UpperBounds, bounded[row-numRows])
+		}
+	}
+	for _, variable := range res.Bounds {
+		if variable < lp.Dim() {
+			mapped.Bounds = append(mapped.Bounds, variable)
+		}
+	}
+	return mapped
+}
+
+func findIIS(lp *StandardLP, pr PivotRule, dense bool) *IIS {
+	f := &iisFinder{lp: lp, pr: pr, dense: dense}
+	allRows := make([]bool, len(lp.ConstraintVector))
+	allBounds := make([]bool, lp.Dim())
+	for i := range allRows {
+		allRows[i] = true
+	}
+	for i := range allBounds {
+		allBounds[i] = true
+	}
+	if !f.infeasible(allRows, allBounds) {
+		return nil
+	}
+
+	for i := range allRows {
+		if allRows[i] {
+			allRows[i] = false
+			if !f.infeasible(allRows, allBounds) {
+				allRows[i] = true
+			}
+		}
+	}
+	for i := range allBounds {
+		if allBounds[i] {
+			allBounds[i] = false
+			if !f.infeasible(allRows, allBounds) {
+				allBounds[i] = true
+			}
+		}
+	}
+
+	res := &IIS{}
+	for i, in := range allRows {
+		if in {
+			res.Rows = append(res.Rows, i)
+		}
+	}
+	for i, in := range allBounds {
+		if in {
+			res.Bounds = append(res.Bounds, i)
+		}
+	}
+	return res
+}
+
+type iisFinder struct {
+	lp    *StandardLP
+	pr    PivotRule
+	dense bool
+}
+
+// infeasible checks if the subset of constraints is
+// infeasible.
+//
+// If so, it removes constraints from the subset which are
+// not needed by the resulting Farkas certificate.
+func (f *iisFinder) infeasible(rows, bounds []bool) bool {
+	sub, rowIndices := f.subproblem(rows, bounds)
+	res := Simplex(sub, f.pr, f.dense)
+	if res.Status != Infeasible {
+		return false
+	}
+	if res.Farkas == nil {
+		return true
+	}
+
+	// A row with a zero multiplier is not used by the
+	// certificate, and neither is the bound of a
+	// variable whose combined column is zero.
+	epsilon := relativeEpsilon * res.Farkas.AbsMax() * float64(len(res.Farkas))
+	combination := make(Vector, f.lp.Dim())
+	for i, y := range res.Farkas {
+		row := rowIndices[i]
+		if math.Abs(y) <= epsilon {
+			rows[row] = false
+		} else {
+			combination.Add(f.lp.ConstraintMatrix.CopyRow(row), y)
+		}
+	}
+	matrixEpsilon := epsilon * f.lp.ConstraintMatrix.AbsMax()
+	for i, x := range combination {
+		if math.Abs(x) <= matrixEpsilon {
+			bounds[i] = false
+		}
+	}
+	return true
+}
+
+// subproblem creates a feasibility problem from a subset
+// of constraints.
+// Variables without a bound are split into a positive and
+// a negative part.
+//
+// It returns the problem and the original index of each
+// of its rows.
+func (f *iisFinder) subproblem(rows, bounds []bool) (*StandardLP, []int) {
+	var rowIndices []int
+	for i, in := range rows {
+		if in {
+			rowIndices = append(rowIndices, i)
+		}
+	}
+	numCols := f.lp.Dim()
+	for _, bounded := range bounds {
+		if !bounded {
+			numCols++
+		}
+	}
+	matrix := NewDenseMatrix(len(rowIndices), numCols)
+	vector := make(Vector, len(rowIndices))
+	for i, row := range rowIndices {
+		vector[i] = f.lp.ConstraintVector[row]
+		values := f.lp.ConstraintMatrix.CopyRow(row)
+		copy(matrix.Row(i), values)
+		negCol := f.lp.Dim()
+		for j, bounded := range bounds {
+			if !bounded {
+				matrix.Set(i, negCol, -values[j])
+				negCol++
+			}
+		}
+	}
+	return &StandardLP{
+		Objective:        make(Vector, numCols),
+		ConstraintMatrix: matrix,
+		ConstraintVector: vector,
+	}, rowIndices
+}
@@ -0,0 +1,37 @@
+package linprog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindIIS(t *testing.T) {
+	// x + y = 1, x = 2, z = 5, w - z = 1.
+	// The constraints x + y = 1, x = 2, and y >= 0 conflict.
+	problem := &StandardLP{
+		Objective: Vector{1, 1, 1, 1},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 4,
+			NumCols: 4,
+			Data: []float64{
+				1, 1, 0, 0,
+				1, 0, 0, 0,
+				0, 0, 1, 0,
+				0, 0, -1, 1,
+			},
+		},
+		ConstraintVector: Vector{1, 2, 5, 1},
+	}
+	iis := FindIIS(problem, BlandPivotRule{}, false)
+	if iis == nil {
+		t.Fatal("expected an IIS")
+	}
+	if !reflect.DeepEqual(iis.Rows, []int{0, 1}) || !reflect.DeepEqual(iis.Bounds, []int{1}) {
+		t.Errorf("unexpected IIS: %v", iis)
+	}
+
+	problem.ConstraintVector[1] = 0.5
+	if iis := FindIIS(problem, BlandPivotRule{}, false); iis != nil {
+		t.Errorf("unexpected IIS for feasible problem: %v", iis)
+	}
+}
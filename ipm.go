@@ -0,0 +1,353 @@
+package linprog
+
+import "math"
+
+// ipmRefinementSteps is the number of times the solution
+// to the normal equations is refined.
+const ipmRefinementSteps = 2
+
+// InteriorPointOptions configures InteriorPoint.
+// Zero fields are replaced with default values.
+type InteriorPointOptions struct {
+	// Tolerance is the relative tolerance for primal
+	// infeasibility, dual infeasibility, and the duality
+	// gap at which a solution is considered optimal.
+	//
+	// The default is 1e-8.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of iterations
+	// before giving up with an IterationLimit status.
+	//
+	// The default is 100.
+	MaxIterations int
+
+	// StepFraction is the fraction of the distance to the
+	// boundary of the positive orthant which is covered by
+	// each step.
+	//
+	// The default is 0.99.
+	StepFraction float64
+
+	// Divergence is the magnitude of the iterates beyond
+	// which the program is deemed infeasible or unbounded.
+	//
+	// The default is 1e10.
+	Divergence float64
+}
+
+func (i *InteriorPointOptions) withDefaults() InteriorPointOptions {
+	var res InteriorPointOptions
+	if i != nil {
+		res = *i
+	}
+	if res.Tolerance == 0 {
+		res.Tolerance = 1e-8
+	}
+	if res.MaxIterations == 0 {
+		res.MaxIterations = 100
+	}
+	if res.StepFraction == 0 {
+		res.StepFraction = 0.99
+	}
+	if res.Divergence == 0 {
+		res.Divergence = 1e10
+	}
+	return res
+}
+
+// InteriorPoint solves a linear program with Mehrotra's
+// predictor-corrector primal-dual interior-point method.
+//
+// Each iteration solves the normal equations A*D*A' for
+// the search direction using a Cholesky factorization,
+// so the cost of an iteration does not depend on how
+// many vertices the program has.
+//
+// The solution is generally not a vertex of the feasible
+// polytope, so the result has no basis or sensitivity
+// analysis. Iterations are counted as phase 2 iterations.
+//
+// Infeasible and unbounded programs are detected when the
+// dual or primal iterates diverge; when possible, the
+// result includes a certificate.
+// If opts is nil, default options are used.
+// Upper bounds are turned into rows with BoundsToRows, and
+// the result is mapped back to lp.
+func InteriorPoint(lp *StandardLP, opts *InteriorPointOptions) *Result {
+	res := interiorPoint(lp.BoundsToRows(), opts)
+	lp.fromBoundRows(res)
+	return res
+}
+
+func interiorPoint(lp *StandardLP, opts *InteriorPointOptions) *Result {
+	o := opts.withDefaults()
+	s := newIPMSolver(lp)
+	res := &Result{Status: IterationLimit}
+
+	x, y, z := s.startingPoint()
+	n := float64(lp.Dim())
+	bNorm := 1 + lp.ConstraintVector.AbsMax()
+	cNorm := 1 + s.costs.AbsMax()
+	for res.Phase2Iterations < o.MaxIterations {
+		primalResidual := append(Vector{}, lp.ConstraintVector...)
+		primalResidual.Add(s.mulA(x), -1)
+		dualResidual := append(Vector{}, s.costs...)
+		dualResidual.Add(s.mulAT(y), -1)
+		dualResidual.Add(z, -1)
+
+		primalObj := s.costs.Dot(x)
+		dualObj := lp.ConstraintVector.Dot(y)
+		if primalResidual.AbsMax()/bNorm < o.Tolerance &&
+			dualResidual.AbsMax()/cNorm < o.Tolerance &&
+			math.Abs(primalObj-dualObj)/(1+math.Abs(primalObj)) < o.Tolerance {
+			res.Status = Optimal
+			break
+		}
+		if x.AbsMax() > o.Divergence*bNorm {
+			res.Status = Unbounded
+			break
+		} else if y.AbsMax() > o.Divergence*cNorm {
+			res.Status = Infeasible
+			break
+		}
+
+		scaling := make(Vector, len(x))
+		for i, xi := range x {
+			scaling[i] = xi / z[i]
+		}
+		chol := s.normalFactorization(scaling)
+		if chol == nil {
+			res.Status = NumericalFailure
+			break
+		}
+		solve := func(complementarity Vector) (dx, dy, dz Vector) {
+			dx = make(Vector, len(x))
+			for i := range dx {
+				dx[i] = (complementarity[i] - x[i]*dualResidual[i]) / z[i]
+			}
+			dy = make(Vector, len(y))
+			dz = append(Vector{}, dualResidual...)
+
+			// Iterative refinement makes up for the poor
+			// conditioning of the normal equations near the
+			// solution.
+			for i := 0; i < ipmRefinementSteps; i++ {
+				rhs := append(Vector{}, primalResidual...)
+				rhs.Add(s.mulA(dx), -1)
+				step := chol.Solve(rhs)
+				atStep := s.mulAT(step)
+				dy.Add(step, 1)
+				dz.Add(atStep, -1)
+				for j, d := range scaling {
+					dx[j] += d * atStep[j]
+				}
+			}
+			return
+		}
+
+		// Predictor (affine scaling) step.
+		complementarity := make(Vector, len(x))
+		for i, xi := range x {
+			complementarity[i] = -xi * z[i]
+		}
+		affX, _, affZ := solve(complementarity)
+		primalStep := math.Min(1, maxStep(x, affX))
+		dualStep := math.Min(1, maxStep(z, affZ))
+		mu := x.Dot(z) / n
+		var affMu float64
+		for i, xi := range x {
+			affMu += (xi + primalStep*affX[i]) * (z[i] + dualStep*affZ[i])
+		}
+		affMu /= n
+		sigma := math.Pow(affMu/mu, 3)
+
+		// Corrector and centering step.
+		for i := range complementarity {
+			complementarity[i] += sigma*mu - affX[i]*affZ[i]
+		}
+		dx, dy, dz := solve(complementarity)
+		primalStep = math.Min(1, o.StepFraction*maxStep(x, dx))
+		dualStep = math.Min(1, o.StepFraction*maxStep(z, dz))
+		x.Add(dx, primalStep)
+		y.Add(dy, dualStep)
+		z.Add(dz, dualStep)
+		res.Phase2Iterations++
+
+		if !finiteVector(x) || !finiteVector(y) || !finiteVector(z) {
+			res.Status = NumericalFailure
+			break
+		}
+	}
+
+	switch res.Status {
+	case Optimal:
+		res.Solution = x
+		res.Objective = lp.Objective.Dot(x)
+		res.Duals = append(Vector{}, y...)
+		res.Duals.Scale(-1)
+		res.ReducedCosts = append(Vector{}, z...)
+		res.ReducedCosts.Scale(-1)
+	case Unbounded:
+		// The iterates diverge along a ray.
+		ray := append(Vector{}, x...)
+		ray.Scale(1 / x.AbsMax())
+		if VerifyRay(lp, ray) {
+			res.Ray = ray
+		}
+	case Infeasible:
+		// The dual iterates diverge along a ray, which is
+		// a Farkas certificate.
+		if dot := y.Dot(lp.ConstraintVector); dot > 0 {
+			certificate := append(Vector{}, y...)
+			certificate.Scale(1 / dot)
+			if VerifyFarkas(lp, certificate) {
+				res.Farkas = certificate
+			}
+		}
+	}
+	return res
+}
+
+// ipmSolver stores the fixed data for the interior-point
+// method, which works on the minimization problem
+//
+//	minimize c'*x subject to A*x = b, x >= 0
+//
+// whose dual is
+//
+//	maximize b'*y subject to A'*y + z = c, z >= 0
+//
+// where c is the negated objective of the original
+// program.
+type ipmSolver struct {
+	lp      *StandardLP
+	columns [][]sparseEntry
+	costs   Vector
+}
+
+func newIPMSolver(lp *StandardLP) *ipmSolver {
+	costs := append(Vector{}, lp.Objective...)
+	costs.Scale(-1)
+	return &ipmSolver{
+		lp:      lp,
+		columns: sparseColumns(lp.ConstraintMatrix),
+		costs:   costs,
+	}
+}
+
+// startingPoint uses Mehrotra's heuristic to find an
+// initial point close to the central path.
+func (s *ipmSolver) startingPoint() (x, y, z Vector) {
+	ones := make(Vector, s.lp.Dim())
+	for i := range ones {
+		ones[i] = 1
+	}
+	chol := s.normalFactorization(ones)
+	if chol == nil {
+		y = make(Vector, len(s.lp.ConstraintVector))
+		x = append(Vector{}, ones...)
+		z = append(Vector{}, ones...)
+		return
+	}
+	x = s.mulAT(chol.Solve(s.lp.ConstraintVector))
+	y = chol.Solve(s.mulA(s.costs))
+	z = append(Vector{}, s.costs...)
+	z.Add(s.mulAT(y), -1)
+
+	shift := func(v Vector) {
+		var min float64
+		for _, x := range v {
+			min = math.Min(min, x)
+		}
+		for i := range v {
+			v[i] -= 1.5 * min
+		}
+	}
+	shift(x)
+	shift(z)
+	product := x.Dot(z)
+	xShift := 0.5 * product / sum(z)
+	zShift := 0.5 * product / sum(x)
+	for i := range x {
+		x[i] += xShift
+		z[i] += zShift
+		if !(x[i] > 0) {
+			x[i] = 1
+		}
+		if !(z[i] > 0) {
+			z[i] = 1
+		}
+	}
+	return
+}
+
+// normalFactorization factorizes A*D*A', where D is a
+// diagonal matrix.
+//
+// A tiny multiple of the identity is added to the matrix
+// so that redundant constraints do not make it singular.
+func (s *ipmSolver) normalFactorization(diagonal Vector) *Cholesky {
+	numRows := len(s.lp.ConstraintVector)
+	matrix := NewDenseMatrix(numRows, numRows)
+	for col, entries := range s.columns {
+		d := diagonal[col]
+		for i, e1 := range entries {
+			for _, e2 := range entries[:i+1] {
+				idx := e1.row*numRows + e2.row
+				if e2.row > e1.row {
+					idx = e2.row*numRows + e1.row
+				}
+				matrix.Data[idx] += d * e1.value * e2.value
+			}
+		}
+	}
+	var maxDiag float64
+	for i := 0; i < numRows; i++ {
+		maxDiag = math.Max(maxDiag, matrix.At(i, i))
+	}
+	for i := 0; i < numRows; i++ {
+		matrix.Set(i, i, matrix.At(i, i)+maxDiag*1e-14)
+	}
+	return NewCholesky(matrix)
+}
+
+func (s *ipmSolver) mulA(x Vector) Vector {
+	res := make(Vector, len(s.lp.ConstraintVector))
+	for col, entries := range s.columns {
+		for _, entry := range entries {
+			res[entry.row] += entry.value * x[col]
+		}
+	}
+	return res
+}
+
+func (s *ipmSolver) mulAT(y Vector) Vector {
+	res := make(Vector, len(s.columns))
+	for col, entries := range s.columns {
+		for _, entry := range entries {
+			res[col] += entry.value * y[entry.row]
+		}
+	}
+	return res
+}
+
+// maxStep finds the largest step t such that v+t*d is
+// non-negative, or +Inf if there is no limit.
+func maxStep(v, d Vector) float64 {
+	res := math.Inf(1)
+	for i, x := range d {
+		if x < 0 {
+			res = math.Min(res, -v[i]/x)
+		}
+	}
+	return res
+}
+
+func sum(v Vector) float64 {
+	var res float64
+	for _, x := range v {
+		res += x
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInteriorPoint(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(15, 30)
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		actual := InteriorPoint(problem, nil)
+		if actual.Status != expected.Status {
+			t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+		}
+		if expected.Status != Optimal {
+			continue
+		}
+		tolerance := 1e-5 * (1 + math.Abs(expected.Objective))
+		if math.Abs(actual.Objective-expected.Objective) > tolerance {
+			t.Errorf("expected objective %f but got %f", expected.Objective,
+				actual.Objective)
+		}
+		if math.Abs(actual.Duals.Dot(problem.ConstraintVector)-actual.Objective) > tolerance {
+			t.Errorf("duality gap for duals %v", actual.Duals)
+		}
+	}
+}
+
+func TestInteriorPointCertificates(t *testing.T) {
+	problem := &StandardLP{
+		Objective: Vector{4.5, 3.5},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 2,
+			Data:    []float64{1, -1},
+		},
+		ConstraintVector: Vector{1},
+	}
+	res := InteriorPoint(problem, nil)
+	if res.Status != Unbounded || !VerifyRay(problem, res.Ray) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Ray)
+	}
+
+	problem.ConstraintMatrix = &DenseMatrix{
+		NumRows: 2,
+		NumCols: 2,
+		Data:    []float64{1, -1, -2, 2},
+	}
+	problem.ConstraintVector = Vector{1, -1.5}
+	res = InteriorPoint(problem, nil)
+	if res.Status != Infeasible || !VerifyFarkas(problem, res.Farkas) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Farkas)
+	}
+
+	problem.ConstraintVector = Vector{1, -2}
+	problem.Objective = Vector{-4.5, 3.5}
+	res = InteriorPoint(problem, nil)
+	if res.Status != Optimal || math.Abs(res.Objective+4.5) > 1e-5 {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Objective)
+	}
+}
@@ -0,0 +1,459 @@
+package linprog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// lpJSON is the JSON encoding of a linear program, which
+// is shared by StandardLP and Model.
+// See StandardLP.UnmarshalJSON for a description.
+type lpJSON struct {
+	Sense           string     `json:"sense,omitempty"`
+	Objective       Vector     `json:"objective"`
+	ObjectiveOffset float64    `json:"objective_offset,omitempty"`
+	Matrix          matrixJSON `json:"matrix"`
+	RHS             Vector     `json:"rhs"`
+	RHSUpper        []*float64 `json:"rhs_upper,omitempty"`
+	Relations       []string   `json:"relations,omitempty"`
+	LowerBounds     []*float64 `json:"lower_bounds,omitempty"`
+	UpperBounds     []*float64 `json:"upper_bounds,omitempty"`
+	VariableNames   []string   `json:"variable_names,omitempty"`
+	ConstraintNames []string   `json:"constraint_names,omitempty"`
+}
+
+type matrixJSON struct {
+	Rows   []int  `json:"rows"`
+	Cols   []int  `json:"cols"`
+	Values Vector `json:"values"`
+}
+
+// MarshalJSON encodes the program in the JSON format
+// described by UnmarshalJSON.
+func (s *StandardLP) MarshalJSON() ([]byte, error) {
+	doc := &lpJSON{
+		Sense:     "max",
+		Objective: s.Objective,
+		Matrix:    encodeMatrixJSON(s.ConstraintMatrix, s.Dim()),
+		RHS:       s.ConstraintVector,
+	}
+	if s.hasUpperBounds() {
+		doc.UpperBounds = encodeBoundsJSON(s.UpperBounds)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a program from JSON.
+//
+// A program is encoded as an object like
+//
+//	{
+//	  "sense": "max",
+//	  "objective": [1, 2],
+//	  "matrix": {"rows": [0, 0, 1], "cols": [0, 1, 1], "values": [1, 1, -1]},
+//	  "rhs": [3, 1],
+//	  "upper_bounds": [null, 5]
+//	}
+//
+// The constraint matrix is given as (row, column, value)
+// triplets, and duplicate entries are summed. The number
+// of variables is the length of the objective, and the
+// number of constraints is the length of the right-hand
+// side. In the upper bounds, null means no bound.
+// The sense and the upper bounds may be omitted.
+//
+// The same format is used by Model, which supports more
+// fields. A document for a StandardLP may include those
+// fields as long as they do not leave standard form, so
+// names are ignored and every relation must be "=".
+func (s *StandardLP) UnmarshalJSON(data []byte) error {
+	var doc lpJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err := doc.check(); err != nil {
+		return err
+	}
+	if doc.Sense == "min" || doc.ObjectiveOffset != 0 {
+		return errors.New("json: standard program must be a maximization with no offset")
+	}
+	for _, r := range doc.Relations {
+		if r != "=" {
+			return errors.New("json: standard program must only have equality constraints")
+		}
+	}
+	for _, x := range doc.LowerBounds {
+		if x == nil || *x != 0 {
+			return errors.New("json: standard program must have lower bounds of zero")
+		}
+	}
+	for i, x := range doc.UpperBounds {
+		if x != nil && !(*x >= 0) {
+			return fmt.Errorf("json: variable %d has a negative upper bound", i)
+		}
+	}
+	*s = StandardLP{
+		Objective:        doc.Objective,
+		ConstraintMatrix: doc.matrix(),
+		ConstraintVector: doc.RHS,
+	}
+	if doc.UpperBounds != nil {
+		s.UpperBounds = decodeBoundsJSON(doc.UpperBounds, math.Inf(1))
+	}
+	return nil
+}
+
+// MarshalJSON encodes the model in the JSON format used by
+// StandardLP, including the objective sense, the
+// relations, the bounds, and the names.
+//
+// Constants in the objective are stored in the field
+// "objective_offset".
+func (m *Model) MarshalJSON() ([]byte, error) {
+	// The variables which GeneralLP adds for range
+	// constraints are left out.
+	g := m.GeneralLP()
+	n := len(m.vars)
+	doc := &lpJSON{
+		Sense:           "max",
+		Objective:       g.Objective[:n],
+		ObjectiveOffset: m.objective.Constant,
+		Matrix:          encodeMatrixJSON(g.ConstraintMatrix, n),
+		RHS:             g.ConstraintVector,
+		Relations:       make([]string, len(m.constraints)),
+		LowerBounds:     encodeBoundsJSON(g.LowerBounds[:n]),
+		UpperBounds:     encodeBoundsJSON(g.UpperBounds[:n]),
+		VariableNames:   make([]string, n),
+		ConstraintNames: make([]string, len(m.constraints)),
+	}
+	if m.minimize {
+		doc.Sense = "min"
+	}
+	for i, v := range m.vars {
+		doc.VariableNames[i] = v.name
+	}
+	for i, c := range m.constraints {
+		doc.ConstraintNames[i] = c.name
+		if c.ranged {
+			if doc.RHSUpper == nil {
+				doc.RHSUpper = make([]*float64, len(m.constraints))
+			}
+			upper := c.upper - c.expr.Constant
+			doc.RHSUpper[i] = &upper
+			doc.Relations[i] = "range"
+			continue
+		}
+		switch c.relation {
+		case Equal:
+			doc.Relations[i] = "="
+		case LessEqual:
+			doc.Relations[i] = "<="
+		case GreaterEqual:
+			doc.Relations[i] = ">="
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a model from the JSON format used
+// by StandardLP, with these extra fields:
+//
+//	{
+//	  "sense": "min",
+//	  "objective_offset": 2,
+//	  "relations": ["<=", "range"],
+//	  "rhs_upper": [null, 4],
+//	  "lower_bounds": [0, null],
+//	  "variable_names": ["x", "y"],
+//	  "constraint_names": ["total", "balance"]
+//	}
+//
+// The sense is "max" or "min", and each relation is "=",
+// "<=", ">=", or "range". A range constraint requires the
+// left-hand side to lie between "rhs" and "rhs_upper",
+// which is null for the other constraints; see
+// Model.AddRangeConstraint. A null lower bound means no
+// bound.
+// Omitted fields default to a maximization with equality
+// constraints and lower bounds of 0.
+// Variables without names are named C0, C1, etc., and
+// constraints without names are named R0, R1, etc.
+func (m *Model) UnmarshalJSON(data []byte) error {
+	var doc lpJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err := doc.check(); err != nil {
+		return err
+	}
+	varNames := doc.VariableNames
+	if varNames == nil {
+		varNames = make([]string, len(doc.Objective))
+		for i := range varNames {
+			varNames[i] = fmt.Sprintf("C%d", i)
+		}
+	}
+	rowNames := doc.ConstraintNames
+	if rowNames == nil {
+		rowNames = make([]string, len(doc.RHS))
+		for i := range rowNames {
+			rowNames[i] = fmt.Sprintf("R%d", i)
+		}
+	}
+	if err := checkUniqueNames(varNames); err != nil {
+		return err
+	}
+	if err := checkUniqueNames(rowNames); err != nil {
+		return err
+	}
+
+	lower := make(Vector, len(doc.Objective))
+	upper := make(Vector, len(doc.Objective))
+	for i := range upper {
+		upper[i] = math.Inf(1)
+	}
+	if doc.LowerBounds != nil {
+		lower = decodeBoundsJSON(doc.LowerBounds, math.Inf(-1))
+	}
+	if doc.UpperBounds != nil {
+		upper = decodeBoundsJSON(doc.UpperBounds, math.Inf(1))
+	}
+	bounds := &GeneralLP{Objective: doc.Objective, LowerBounds: lower, UpperBounds: upper}
+	if err := bounds.Validate(); err != nil {
+		return fmt.Errorf("json: %s", err)
+	}
+
+	res := NewModel()
+	vars := make([]Var, len(doc.Objective))
+	objective := Const(doc.ObjectiveOffset)
+	for i, name := range varNames {
+		vars[i] = res.AddVar(name, lower[i], upper[i])
+		if c := doc.Objective[i]; c != 0 {
+			objective = objective.Add(vars[i].Mul(c))
+		}
+	}
+	if doc.Sense == "min" {
+		res.Minimize(objective)
+	} else {
+		res.Maximize(objective)
+	}
+	rows := make([]Expr, len(doc.RHS))
+	for i, row := range doc.Matrix.Rows {
+		rows[row] = rows[row].Add(vars[doc.Matrix.Cols[i]].Mul(doc.Matrix.Values[i]))
+	}
+	for i, name := range rowNames {
+		relation := Equal
+		if doc.Relations != nil {
+			switch doc.Relations[i] {
+			case "<=":
+				relation = LessEqual
+			case ">=":
+				relation = GreaterEqual
+			case "range":
+				res.AddRangeConstraint(name, rows[i], doc.RHS[i], *doc.RHSUpper[i])
+				continue
+			}
+		}
+		res.AddConstraint(name, rows[i], relation, doc.RHS[i])
+	}
+	*m = *res
+	return nil
+}
+
+// check validates the sizes and values of the fields.
+func (l *lpJSON) check() error {
+	numVars, numRows := len(l.Objective), len(l.RHS)
+	if l.Sense != "" && l.Sense != "max" && l.Sense != "min" {
+		return fmt.Errorf("json: unknown sense %q", l.Sense)
+	}
+	if len(l.Matrix.Cols) != len(l.Matrix.Rows) || len(l.Matrix.Values) != len(l.Matrix.Rows) {
+		return errors.New("json: matrix triplet arrays differ in length")
+	}
+	for i, row := range l.Matrix.Rows {
+		if col := l.Matrix.Cols[i]; row < 0 || row >= numRows || col < 0 || col >= numVars {
+			return fmt.Errorf("json: matrix entry (%d, %d) out of bounds", row, col)
+		}
+	}
+	for name, length := range map[string]int{
+		"relations":        len(l.Relations),
+		"rhs_upper":        len(l.RHSUpper),
+		"lower_bounds":     len(l.LowerBounds),
+		"upper_bounds":     len(l.UpperBounds),
+		"variable_names":   len(l.VariableNames),
+		"constraint_names": len(l.ConstraintNames),
+	} {
+		expected := numVars
+		if name == "relations" || name == "rhs_upper" || name == "constraint_names" {
+			expected = numRows
+		}
+		if length != 0 && length != expected {
+			return fmt.Errorf("json: expected %d %s but got %d", expected, name, length)
+		}
+	}
+	for i, r := range l.Relations {
+		if r == "range" {
+			if l.RHSUpper == nil || l.RHSUpper[i] == nil {
+				return fmt.Errorf("json: range constraint %d has no upper side", i)
+			}
+			lower, upper := l.RHS[i], *l.RHSUpper[i]
+			if !(lower <= upper) || math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+				return fmt.Errorf("json: range constraint %d is invalid: [%g, %g]", i,
+					lower, upper)
+			}
+		} else if r != "=" && r != "<=" && r != ">=" {
+			return fmt.Errorf("json: unknown relation %q", r)
+		}
+	}
+	return nil
+}
+
+func (l *lpJSON) matrix() Matrix {
+	res := NewSparseMatrix(len(l.RHS), len(l.Objective))
+	for i, row := range l.Matrix.Rows {
+		col := l.Matrix.Cols[i]
+		res.Set(row, col, res.At(row, col)+l.Matrix.Values[i])
+	}
+	return res
+}
+
+// encodeMatrixJSON encodes the entries of the first
+// numCols columns of m.
+func encodeMatrixJSON(m Matrix, numCols int) matrixJSON {
+	res := matrixJSON{Rows: []int{}, Cols: []int{}, Values: Vector{}}
+	for i := 0; i < m.Rows(); i++ {
+		for j, x := range m.CopyRow(i)[:numCols] {
+			if x != 0 {
+				res.Rows = append(res.Rows, i)
+				res.Cols = append(res.Cols, j)
+				res.Values = append(res.Values, x)
+			}
+		}
+	}
+	return res
+}
+
+// encodeBoundsJSON encodes infinite bounds as null.
+func encodeBoundsJSON(v Vector) []*float64 {
+	res := make([]*float64, len(v))
+	for i, x := range v {
+		if !math.IsInf(x, 0) {
+			x := x
+			res[i] = &x
+		}
+	}
+	return res
+}
+
+// decodeBoundsJSON decodes null bounds as inf.
+func decodeBoundsJSON(v []*float64, inf float64) Vector {
+	res := make(Vector, len(v))
+	for i, x := range v {
+		if x == nil {
+			res[i] = inf
+		} else {
+			res[i] = *x
+		}
+	}
+	return res
+}
+
+func checkUniqueNames(names []string) error {
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("json: duplicate name %q", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// resultJSON is the JSON encoding of a Result.
+type resultJSON struct {
+	Status           SimplexStatus `json:"status"`
+	Objective        *float64      `json:"objective,omitempty"`
+	Primal           Vector        `json:"primal,omitempty"`
+	Dual             Vector        `json:"dual,omitempty"`
+	ReducedCosts     Vector        `json:"reduced_costs,omitempty"`
+	Basis            []int         `json:"basis,omitempty"`
+	Ray              Vector        `json:"ray,omitempty"`
+	Farkas           Vector        `json:"farkas,omitempty"`
+	Phase1Iterations int           `json:"phase1_iterations"`
+	Phase2Iterations int           `json:"phase2_iterations"`
+	Perturbed        bool          `json:"perturbed,omitempty"`
+}
+
+// MarshalJSON encodes the result as an object with the
+// fields "status", "objective", "primal", "dual",
+// "reduced_costs", "basis", "ray", "farkas",
+// "phase1_iterations", "phase2_iterations", and
+// "perturbed".
+//
+// The status is a string such as "Optimal". Fields which
+// are nil or false in the result are omitted. The
+// objective is omitted unless the status is Optimal or the
+// algorithm was stopped early with a solution, since the
+// objective of an unbounded program has no finite value.
+// Sensitivity analysis is not encoded.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	doc := &resultJSON{
+		Status:           r.Status,
+		Primal:           r.Solution,
+		Dual:             r.Duals,
+		ReducedCosts:     r.ReducedCosts,
+		Basis:            r.Basis,
+		Ray:              r.Ray,
+		Farkas:           r.Farkas,
+		Phase1Iterations: r.Phase1Iterations,
+		Phase2Iterations: r.Phase2Iterations,
+		Perturbed:        r.Perturbed,
+	}
+	hasObjective := r.Status == Optimal || interrupted(r.Status)
+	if hasObjective && r.Solution != nil && !math.IsNaN(r.Objective) &&
+		!math.IsInf(r.Objective, 0) {
+		doc.Objective = &r.Objective
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var doc resultJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*r = Result{
+		Status:           doc.Status,
+		Solution:         doc.Primal,
+		Duals:            doc.Dual,
+		ReducedCosts:     doc.ReducedCosts,
+		Basis:            doc.Basis,
+		Ray:              doc.Ray,
+		Farkas:           doc.Farkas,
+		Phase1Iterations: doc.Phase1Iterations,
+		Phase2Iterations: doc.Phase2Iterations,
+		Perturbed:        doc.Perturbed,
+	}
+	if doc.Objective != nil {
+		r.Objective = *doc.Objective
+	}
+	return nil
+}
+
+// MarshalText encodes the status as its name.
+func (s SimplexStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a status from its name.
+func (s *SimplexStatus) UnmarshalText(text []byte) error {
+	for status := Working; status <= Cancelled; status++ {
+		if status.String() == string(text) {
+			*s = status
+			return nil
+		}
+	}
+	return fmt.Errorf("json: unknown status %q", text)
+}
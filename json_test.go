@@ -0,0 +1,183 @@
+package linprog
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStandardLPJSON(t *testing.T) {
+	problem := randomStandardLP(4, 7)
+	problem.ConstraintMatrix.Set(1, 2, 0)
+	problem.UpperBounds = Vector{1, math.Inf(1), 2, 3, math.Inf(1), 4, 5}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual StandardLP
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := &GeneralLP{
+		Objective:        problem.Objective,
+		ConstraintMatrix: problem.ConstraintMatrix,
+		ConstraintVector: problem.ConstraintVector,
+		UpperBounds:      problem.UpperBounds,
+	}
+	decoded := &GeneralLP{
+		Objective:        actual.Objective,
+		ConstraintMatrix: actual.ConstraintMatrix,
+		ConstraintVector: actual.ConstraintVector,
+		UpperBounds:      actual.UpperBounds,
+	}
+	if !generalLPsEqual(decoded, expected) {
+		t.Errorf("round trip changed the program: %s", data)
+	}
+
+	doc := `{"objective": [1, 2], "rhs": [3],
+		"matrix": {"rows": [0, 0, 0], "cols": [0, 1, 0], "values": [1, 1, 2]}}`
+	if err := json.Unmarshal([]byte(doc), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.UpperBounds != nil || actual.ConstraintMatrix.At(0, 0) != 3 ||
+		actual.ConstraintMatrix.At(0, 1) != 1 {
+		t.Errorf("unexpected program: %+v", actual)
+	}
+
+	for i, doc := range []string{
+		`{"objective": [1], "rhs": [1], "matrix": {"rows": [1], "cols": [0], "values": [1]}}`,
+		`{"objective": [1], "rhs": [1], "matrix": {"rows": [0], "cols": [0], "values": []}}`,
+		`{"objective": [1], "rhs": [1], "matrix": {}, "relations": ["<="]}`,
+		`{"objective": [1], "rhs": [1], "matrix": {}, "sense": "min"}`,
+		`{"objective": [1], "rhs": [1], "matrix": {}, "upper_bounds": [1, 2]}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &actual); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestModelJSON(t *testing.T) {
+	model, err := ReadLP(strings.NewReader(testLPFormat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual Model
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !generalLPsEqual(actual.GeneralLP(), model.GeneralLP()) {
+		t.Errorf("round trip changed the model: %s", data)
+	}
+	if actual.objective.Constant != model.objective.Constant {
+		t.Errorf("expected offset %f but got %f", model.objective.Constant,
+			actual.objective.Constant)
+	}
+	if !reflect.DeepEqual(actual.nameToVar, model.nameToVar) ||
+		!reflect.DeepEqual(actual.nameToRow, model.nameToRow) {
+		t.Errorf("round trip changed the names: %s", data)
+	}
+
+	doc := `{"objective": [1, 1], "rhs": [2], "sense": "min",
+		"matrix": {"rows": [0, 0], "cols": [0, 1], "values": [1, 1]},
+		"relations": [">="], "lower_bounds": [null, 0], "upper_bounds": [3, null]}`
+	if err := json.Unmarshal([]byte(doc), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := actual.Var("C1"); !ok || actual.constraints[0].name != "R0" {
+		t.Error("expected default names")
+	}
+	solution := actual.Solve(BlandPivotRule{}, false)
+	if solution.Status() != Optimal || math.Abs(solution.Objective-2) > 1e-8 {
+		t.Errorf("unexpected solution: %v %f", solution.Status(), solution.Objective)
+	}
+
+	doc = `{"objective": [1, 1], "rhs": [4], "relations": ["range"], "rhs_upper": [6],
+		"matrix": {"rows": [0, 0], "cols": [0, 1], "values": [1, 2]}}`
+	if err := json.Unmarshal([]byte(doc), &actual); err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(&actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranged Model
+	if err := json.Unmarshal(data, &ranged); err != nil {
+		t.Fatal(err)
+	}
+	if !generalLPsEqual(ranged.GeneralLP(), actual.GeneralLP()) {
+		t.Errorf("round trip changed the range constraint: %s", data)
+	}
+	solution = ranged.Solve(BlandPivotRule{}, false)
+	if solution.Status() != Optimal || math.Abs(solution.Objective-6) > 1e-8 {
+		t.Errorf("unexpected solution: %v %f", solution.Status(), solution.Objective)
+	}
+
+	for i, doc := range []string{
+		`{"objective": [1, 1], "rhs": [], "matrix": {}, "variable_names": ["x", "x"]}`,
+		`{"objective": [1], "rhs": [], "matrix": {}, "upper_bounds": [-1]}`,
+		`{"objective": [1], "rhs": [1], "matrix": {}, "relations": ["range"]}`,
+		`{"objective": [1], "rhs": [1], "matrix": {}, "relations": ["range"],
+			"rhs_upper": [0]}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &actual); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	problem := randomStandardLP(5, 10)
+	problem.UpperBounds = make(Vector, problem.Dim())
+	for i := range problem.UpperBounds {
+		problem.UpperBounds[i] = 10
+	}
+	res := Simplex(problem, BlandPivotRule{}, true)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	res.Sensitivity = nil
+	res.Perturbed = true
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual Result
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&actual, res) {
+		t.Errorf("round trip changed the result: %s", data)
+	}
+
+	data, err = json.Marshal(&Result{Status: Infeasible, Farkas: Vector{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"status":"Infeasible","farkas":[1,2],"phase1_iterations":0,` +
+		`"phase2_iterations":0}`
+	if string(data) != expected {
+		t.Errorf("expected %s but got %s", expected, data)
+	}
+
+	data, err = json.Marshal(&Result{Status: Unbounded, Solution: Vector{0}, Ray: Vector{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"status":"Unbounded","primal":[0],"ray":[1],"phase1_iterations":0,` +
+		`"phase2_iterations":0}`
+	if string(data) != expected {
+		t.Errorf("expected %s but got %s", expected, data)
+	}
+	if err := json.Unmarshal([]byte(`{"status": "Done"}`), &actual); err == nil {
+		t.Error("expected an error for an unknown status")
+	}
+}
@@ -511,3 +511,24 @@ func (r RowBlockMatrix) CopyCol(i int) Vector {
 	}
 	return res
 }
+
+// A sparseEntry is a non-zero entry in a column of a
+// matrix.
+type sparseEntry struct {
+	row   int
+	value float64
+}
+
+// sparseColumns extracts the non-zero entries of each
+// column of a matrix.
+func sparseColumns(m Matrix) [][]sparseEntry {
+	res := make([][]sparseEntry, m.Cols())
+	for row := 0; row < m.Rows(); row++ {
+		for col, x := range m.CopyRow(row) {
+			if x != 0 {
+				res[col] = append(res[col], sparseEntry{row, x})
+			}
+		}
+	}
+	return res
+}
@@ -9,10 +9,22 @@ package linprog
 //
 // Where c is the objective vector, A is the constraint
 // matrix, and b is the constraint vector.
+//
+// The variables are non-negative, and they may optionally
+// have upper bounds as well.
 type StandardLP struct {
 	Objective        Vector
 	ConstraintMatrix Matrix
 	ConstraintVector Vector
+
+	// UpperBounds stores an upper bound for each variable,
+	// which may be +Inf.
+	// If it is nil, no variable has an upper bound.
+	//
+	// Upper bounds are handled natively by the tableau
+	// simplex method. Other algorithms require them to be
+	// converted to constraints with BoundsToRows.
+	UpperBounds Vector
 }
 
 // Dim gets the number of variables in the program.
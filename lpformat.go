@@ -0,0 +1,780 @@
+package linprog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// lpNameSymbols stores the characters other than letters
+// and digits which may appear in names in the LP format.
+const lpNameSymbols = "!\"#$%&()/,.;?@_`'{}|~"
+
+// lpTermsPerLine is the number of terms which WriteLP puts
+// on each line of an expression.
+const lpTermsPerLine = 8
+
+// ReadLP reads a model in the CPLEX LP format.
+//
+// The objective, constraints, and bounds sections are
+// supported, along with the general and binary sections.
+// Since a Model is continuous, integrality is dropped, so
+// general variables are treated like any other variable
+// and binary variables are bounded between 0 and 1.
+//
+// Variables are added to the model in the order in which
+// they first appear, with a default lower bound of 0.
+// Unnamed constraints are named R1, R2, etc. after their
+// positions, as CPLEX does.
+// A constraint of the form l <= expr <= u becomes a range
+// constraint; see Model.AddRangeConstraint.
+func ReadLP(r io.Reader) (*Model, error) {
+	p := &lpParser{
+		varIndex: map[string]int{},
+		rowNames: map[string]bool{},
+	}
+	if err := p.readSections(r); err != nil {
+		return nil, err
+	}
+	for _, s := range p.sections {
+		p.tokens = s.tokens
+		p.pos = 0
+		var err error
+		switch s.kind {
+		case "objective":
+			err = p.parseObjective()
+		case "constraints":
+			err = p.parseConstraints()
+		case "bounds":
+			err = p.parseBounds()
+		case "general", "binary":
+			err = p.parseVarList(s.kind == "binary")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.model()
+}
+
+// WriteLP writes a model in the CPLEX LP format.
+//
+// An error is returned if a name in the model cannot be
+// represented in the LP format.
+func WriteLP(w io.Writer, m *Model) error {
+	for _, v := range m.vars {
+		if err := checkLPName(v.name); err != nil {
+			return err
+		}
+	}
+	for _, c := range m.constraints {
+		if err := checkLPName(c.name); err != nil {
+			return err
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	if m.minimize {
+		fmt.Fprintln(buf, "Minimize")
+	} else {
+		fmt.Fprintln(buf, "Maximize")
+	}
+	fmt.Fprintf(buf, " obj: %s\n", m.formatLPExpr(m.objective, true))
+	fmt.Fprintln(buf, "Subject To")
+	for _, c := range m.constraints {
+		if c.ranged {
+			fmt.Fprintf(buf, " %s: %s <= %s <= %s\n", c.name,
+				formatLPNumber(c.rhs-c.expr.Constant), m.formatLPExpr(c.expr, false),
+				formatLPNumber(c.upper-c.expr.Constant))
+			continue
+		}
+		var relation string
+		switch c.relation {
+		case Equal:
+			relation = "="
+		case LessEqual:
+			relation = "<="
+		case GreaterEqual:
+			relation = ">="
+		}
+		fmt.Fprintf(buf, " %s: %s %s %s\n", c.name, m.formatLPExpr(c.expr, false), relation,
+			formatLPNumber(c.rhs-c.expr.Constant))
+	}
+	fmt.Fprintln(buf, "Bounds")
+	for _, v := range m.vars {
+		lower, upper := v.lower, v.upper
+		switch {
+		case lower == upper:
+			fmt.Fprintf(buf, " %s = %s\n", v.name, formatLPNumber(lower))
+		case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+			fmt.Fprintf(buf, " %s free\n", v.name)
+		case lower == 0 && math.IsInf(upper, 1):
+		case lower == 0 && upper >= 0:
+			fmt.Fprintf(buf, " %s <= %s\n", v.name, formatLPNumber(upper))
+		case math.IsInf(upper, 1):
+			fmt.Fprintf(buf, " %s >= %s\n", v.name, formatLPNumber(lower))
+		default:
+			fmt.Fprintf(buf, " %s <= %s <= %s\n", formatLPNumber(lower), v.name,
+				formatLPNumber(upper))
+		}
+	}
+	fmt.Fprintln(buf, "End")
+	return buf.Flush()
+}
+
+// formatLPExpr formats the terms of an expression, with
+// coefficients of the same variable combined.
+// The constant is only included if withConstant is true.
+func (m *Model) formatLPExpr(e Expr, withConstant bool) string {
+	coeffs := make(Vector, len(m.vars))
+	used := make([]bool, len(m.vars))
+	for _, t := range e.Terms {
+		coeffs[t.Var.index] += t.Coeff
+		used[t.Var.index] = true
+	}
+	var parts []string
+	for i, v := range m.vars {
+		if used[i] {
+			parts = append(parts, formatLPTerm(coeffs[i], v.name))
+		}
+	}
+	if withConstant && e.Constant != 0 {
+		parts = append(parts, formatLPTerm(e.Constant, ""))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+
+	var res strings.Builder
+	for i, part := range parts {
+		if i > 0 && i%lpTermsPerLine == 0 {
+			res.WriteString("\n    ")
+		} else if i > 0 {
+			res.WriteString(" ")
+		}
+		res.WriteString(part)
+	}
+	return strings.TrimPrefix(res.String(), "+ ")
+}
+
+// formatLPTerm formats a signed term, or a constant if
+// the name is empty.
+func formatLPTerm(coeff float64, name string) string {
+	sign := "+"
+	if coeff < 0 {
+		sign = "-"
+	}
+	if name == "" {
+		return sign + " " + formatLPNumber(math.Abs(coeff))
+	} else if math.Abs(coeff) == 1 {
+		return sign + " " + name
+	}
+	return sign + " " + formatLPNumber(math.Abs(coeff)) + " " + name
+}
+
+func formatLPNumber(x float64) string {
+	if math.IsInf(x, 1) {
+		return "inf"
+	} else if math.IsInf(x, -1) {
+		return "-inf"
+	}
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// checkLPName returns an error if a name cannot be
+// written in the LP format.
+func checkLPName(name string) error {
+	if name == "" {
+		return fmt.Errorf("lp: empty name")
+	}
+	if strings.ContainsRune("0123456789.", rune(name[0])) {
+		return fmt.Errorf("lp: name %q starts with a digit or period", name)
+	}
+	for _, r := range name {
+		if !isLPNameRune(r) {
+			return fmt.Errorf("lp: name %q contains %q", name, r)
+		}
+	}
+	lower := strings.ToLower(name)
+	if _, ok := lpSectionKeyword(lower); ok || lower == "subject" || lower == "such" ||
+		lower == "free" || isLPInfinity(lower) {
+		return fmt.Errorf("lp: name %q is a keyword", name)
+	}
+	return nil
+}
+
+func isLPNameRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) ||
+		strings.ContainsRune(lpNameSymbols, r))
+}
+
+// lpSectionStart checks if the words of a line start a
+// section, and returns the kind of section and the number
+// of words in the keyword.
+func lpSectionStart(fields []string) (string, int, bool) {
+	first := strings.ToLower(fields[0])
+	second := ""
+	if len(fields) > 1 {
+		second = strings.ToLower(fields[1])
+	}
+	if (first == "subject" && second == "to") || (first == "such" && second == "that") {
+		return "constraints", 2, true
+	}
+	kind, ok := lpSectionKeyword(first)
+	return kind, 1, ok
+}
+
+// lpSectionKeyword checks if a lowercase word is a
+// one-word section keyword, and returns the kind of
+// section.
+func lpSectionKeyword(word string) (string, bool) {
+	switch word {
+	case "maximize", "maximum", "max":
+		return "maximize", true
+	case "minimize", "minimum", "min":
+		return "minimize", true
+	case "st", "s.t.", "st.":
+		return "constraints", true
+	case "bounds", "bound":
+		return "bounds", true
+	case "general", "generals", "gen", "integer", "integers":
+		return "general", true
+	case "binary", "binaries", "bin":
+		return "binary", true
+	case "semi-continuous", "semis", "semi", "sos":
+		return "unsupported", true
+	case "end":
+		return "end", true
+	}
+	return "", false
+}
+
+type lpTokenKind int
+
+const (
+	lpNumber lpTokenKind = iota
+	lpName
+	lpSign
+	lpRelation
+	lpColon
+)
+
+type lpToken struct {
+	kind  lpTokenKind
+	text  string
+	value float64
+	line  int
+
+	// relation is set for lpRelation tokens.
+	relation ConstraintType
+}
+
+type lpSection struct {
+	kind   string
+	tokens []lpToken
+}
+
+type lpTerm struct {
+	coeff float64
+	name  string
+}
+
+type lpConstraint struct {
+	name     string
+	terms    []lpTerm
+	constant float64
+	relation ConstraintType
+	rhs      float64
+
+	// ranged indicates a constraint lower <= expr <= rhs,
+	// in which case relation is unused.
+	ranged bool
+	lower  float64
+}
+
+// lpParser accumulates the contents of an LP file.
+type lpParser struct {
+	sections []*lpSection
+	tokens   []lpToken
+	pos      int
+
+	minimize    bool
+	objective   []lpTerm
+	objConstant float64
+	constraints []lpConstraint
+	rowNames    map[string]bool
+
+	varNames []string
+	varIndex map[string]int
+	lower    []float64
+	upper    []float64
+}
+
+// readSections splits a file into sections, tokenizing
+// the contents of each section.
+func (p *lpParser) readSections(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	var section *lpSection
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '\\'); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if kind, numWords, ok := lpSectionStart(fields); ok {
+			for _, word := range fields[:numWords] {
+				line = strings.TrimSpace(line)[len(word):]
+			}
+			switch kind {
+			case "unsupported":
+				return fmt.Errorf("lp: line %d: unsupported section %q", lineNum, fields[0])
+			case "end":
+				return scanner.Err()
+			case "minimize", "maximize":
+				if p.sections != nil {
+					return fmt.Errorf("lp: line %d: unexpected objective", lineNum)
+				}
+				p.minimize = kind == "minimize"
+				kind = "objective"
+			}
+			section = &lpSection{kind: kind}
+			p.sections = append(p.sections, section)
+		}
+		if section == nil || p.sections[0].kind != "objective" {
+			return fmt.Errorf("lp: line %d: expected an objective sense", lineNum)
+		}
+		tokens, err := tokenizeLP(line, lineNum)
+		if err != nil {
+			return err
+		}
+		section.tokens = append(section.tokens, tokens...)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("lp: missing End")
+}
+
+func tokenizeLP(line string, lineNum int) ([]lpToken, error) {
+	var res []lpToken
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '+' || c == '-':
+			res = append(res, lpToken{kind: lpSign, text: line[i : i+1], line: lineNum})
+			i++
+		case c == ':':
+			res = append(res, lpToken{kind: lpColon, text: ":", line: lineNum})
+			i++
+		case c == '<' || c == '>' || c == '=':
+			end := i + 1
+			if end < len(line) && strings.IndexByte("<>=", line[end]) != -1 &&
+				line[end] != c {
+				end++
+			}
+			text := line[i:end]
+			token := lpToken{kind: lpRelation, text: text, line: lineNum}
+			switch {
+			case strings.Contains(text, "<"):
+				token.relation = LessEqual
+			case strings.Contains(text, ">"):
+				token.relation = GreaterEqual
+			default:
+				token.relation = Equal
+			}
+			res = append(res, token)
+			i = end
+		case (c >= '0' && c <= '9') || c == '.':
+			end := i
+			for end < len(line) && (line[end] >= '0' && line[end] <= '9' || line[end] == '.') {
+				end++
+			}
+			if end < len(line) && (line[end] == 'e' || line[end] == 'E') {
+				exp := end + 1
+				if exp < len(line) && (line[exp] == '+' || line[exp] == '-') {
+					exp++
+				}
+				if exp < len(line) && line[exp] >= '0' && line[exp] <= '9' {
+					end = exp
+					for end < len(line) && line[end] >= '0' && line[end] <= '9' {
+						end++
+					}
+				}
+			}
+			value, err := strconv.ParseFloat(line[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("lp: line %d: invalid number %q", lineNum, line[i:end])
+			}
+			res = append(res, lpToken{kind: lpNumber, text: line[i:end], value: value,
+				line: lineNum})
+			i = end
+		case isLPNameRune(rune(c)):
+			end := i
+			for end < len(line) && isLPNameRune(rune(line[end])) {
+				end++
+			}
+			res = append(res, lpToken{kind: lpName, text: line[i:end], line: lineNum})
+			i = end
+		default:
+			return nil, fmt.Errorf("lp: line %d: unexpected character %q", lineNum, c)
+		}
+	}
+	return res, nil
+}
+
+func (p *lpParser) peek(offset int) *lpToken {
+	if p.pos+offset >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos+offset]
+}
+
+func (p *lpParser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if t := p.peek(0); t != nil {
+		return fmt.Errorf("lp: line %d: %s", t.line, msg)
+	} else if len(p.tokens) > 0 {
+		return fmt.Errorf("lp: line %d: %s", p.tokens[len(p.tokens)-1].line, msg)
+	}
+	return fmt.Errorf("lp: %s", msg)
+}
+
+// label parses an optional "name:" prefix.
+func (p *lpParser) label() string {
+	if t := p.peek(0); t != nil && t.kind == lpName {
+		if colon := p.peek(1); colon != nil && colon.kind == lpColon {
+			p.pos += 2
+			return t.text
+		}
+	}
+	return ""
+}
+
+// expr parses a sum of terms and constants.
+// Every term after the first must start with a sign.
+func (p *lpParser) expr() ([]lpTerm, float64, error) {
+	var terms []lpTerm
+	var constant float64
+	for first := true; ; first = false {
+		sign := 1.0
+		hasSign := false
+		for t := p.peek(0); t != nil && t.kind == lpSign; t = p.peek(0) {
+			if t.text == "-" {
+				sign = -sign
+			}
+			hasSign = true
+			p.pos++
+		}
+		t := p.peek(0)
+		if !first && !hasSign {
+			return terms, constant, nil
+		}
+		if t == nil || (t.kind != lpNumber && t.kind != lpName) {
+			if hasSign {
+				return nil, 0, p.errorf("expected a term")
+			}
+			return terms, constant, nil
+		}
+		p.pos++
+		if t.kind == lpName {
+			terms = append(terms, lpTerm{coeff: sign, name: t.text})
+		} else if next := p.peek(0); next != nil && next.kind == lpName &&
+			!p.startsLabel() {
+			p.pos++
+			terms = append(terms, lpTerm{coeff: sign * t.value, name: next.text})
+		} else {
+			constant += sign * t.value
+		}
+	}
+}
+
+// startsLabel checks if the current token starts a
+// "name:" label.
+func (p *lpParser) startsLabel() bool {
+	colon := p.peek(1)
+	return colon != nil && colon.kind == lpColon
+}
+
+// signedNumber parses a number with optional signs,
+// allowing infinite values.
+func (p *lpParser) signedNumber() (float64, error) {
+	sign := 1.0
+	for t := p.peek(0); t != nil && t.kind == lpSign; t = p.peek(0) {
+		if t.text == "-" {
+			sign = -sign
+		}
+		p.pos++
+	}
+	t := p.peek(0)
+	if t != nil && t.kind == lpNumber {
+		p.pos++
+		return sign * t.value, nil
+	} else if t != nil && t.kind == lpName && isLPInfinity(t.text) {
+		p.pos++
+		return sign * math.Inf(1), nil
+	}
+	return 0, p.errorf("expected a number")
+}
+
+func isLPInfinity(s string) bool {
+	s = strings.ToLower(s)
+	return s == "inf" || s == "infinity"
+}
+
+func (p *lpParser) parseObjective() error {
+	p.label()
+	terms, constant, err := p.expr()
+	if err != nil {
+		return err
+	}
+	if p.peek(0) != nil {
+		return p.errorf("unexpected %q in objective", p.peek(0).text)
+	}
+	p.objective = terms
+	p.objConstant = constant
+	for _, t := range terms {
+		p.variable(t.name)
+	}
+	return nil
+}
+
+func (p *lpParser) parseConstraints() error {
+	for p.peek(0) != nil {
+		name := p.label()
+		if name == "" {
+			name = fmt.Sprintf("R%d", len(p.constraints)+1)
+		}
+		if p.rowNames[name] {
+			return p.errorf("duplicate constraint %q", name)
+		}
+		p.rowNames[name] = true
+		// A range constraint starts with a value, as in
+		// l <= expr <= u.
+		var leading *float64
+		var leadingRel ConstraintType
+		if p.startsRange() {
+			value, err := p.signedNumber()
+			if err != nil {
+				return err
+			}
+			leading = &value
+			leadingRel = p.peek(0).relation
+			p.pos++
+		}
+		terms, constant, err := p.expr()
+		if err != nil {
+			return err
+		}
+		rel := p.peek(0)
+		if rel == nil || rel.kind != lpRelation {
+			return p.errorf("expected a relation in constraint %q", name)
+		}
+		p.pos++
+		rhs, err := p.signedNumber()
+		if err != nil {
+			return err
+		}
+		for _, t := range terms {
+			p.variable(t.name)
+		}
+		c := lpConstraint{
+			name:     name,
+			terms:    terms,
+			constant: constant,
+			relation: rel.relation,
+			rhs:      rhs,
+		}
+		if leading != nil {
+			if leadingRel != rel.relation || rel.relation == Equal {
+				return p.errorf("mismatched relations in range %q", name)
+			}
+			c.ranged = true
+			c.lower = *leading
+			if rel.relation == GreaterEqual {
+				c.lower, c.rhs = c.rhs, c.lower
+			}
+			if !(c.lower <= c.rhs) || math.IsInf(c.lower, 0) || math.IsInf(c.rhs, 0) {
+				return p.errorf("invalid range %q: [%g, %g]", name, c.lower, c.rhs)
+			}
+		}
+		p.constraints = append(p.constraints, c)
+	}
+	return nil
+}
+
+// startsRange checks if the next tokens are a number and
+// a relation followed by an expression with a variable and
+// another relation, which make up a range constraint.
+//
+// A constant expression, as in 0 >= -1, is not the start
+// of a range.
+func (p *lpParser) startsRange() bool {
+	offset := 0
+	for t := p.peek(offset); t != nil && t.kind == lpSign; t = p.peek(offset) {
+		offset++
+	}
+	if t, next := p.peek(offset), p.peek(offset+1); t == nil || t.kind != lpNumber ||
+		next == nil || next.kind != lpRelation {
+		return false
+	}
+	hasTerm := false
+	for offset += 2; ; offset++ {
+		t := p.peek(offset)
+		if t == nil || t.kind == lpColon {
+			return false
+		}
+		switch t.kind {
+		case lpRelation:
+			return hasTerm
+		case lpName:
+			if next := p.peek(offset + 1); next != nil && next.kind == lpColon {
+				return false
+			}
+			hasTerm = true
+		}
+	}
+}
+
+func (p *lpParser) parseBounds() error {
+	for p.peek(0) != nil {
+		// A bound may start with a value, as in l <= x.
+		var leading *float64
+		var leadingRel ConstraintType
+		if t := p.peek(0); t.kind != lpName || isLPInfinity(t.text) {
+			value, err := p.signedNumber()
+			if err != nil {
+				return err
+			}
+			rel := p.peek(0)
+			if rel == nil || rel.kind != lpRelation {
+				return p.errorf("expected a relation in bound")
+			}
+			p.pos++
+			leading = &value
+			leadingRel = rel.relation
+		}
+		t := p.peek(0)
+		if t == nil || t.kind != lpName {
+			return p.errorf("expected a variable in bound")
+		}
+		p.pos++
+		v := p.variable(t.text)
+		if leading != nil {
+			p.applyBound(v, reverseRelation(leadingRel), *leading)
+		}
+		next := p.peek(0)
+		if leading == nil && next != nil && next.kind == lpName &&
+			strings.ToLower(next.text) == "free" {
+			p.pos++
+			p.lower[v] = math.Inf(-1)
+			p.upper[v] = math.Inf(1)
+		} else if next != nil && next.kind == lpRelation {
+			p.pos++
+			value, err := p.signedNumber()
+			if err != nil {
+				return err
+			}
+			p.applyBound(v, next.relation, value)
+		} else if leading == nil {
+			return p.errorf("expected a relation in bound for %q", t.text)
+		}
+	}
+	return nil
+}
+
+// applyBound applies a bound x (relation) value.
+func (p *lpParser) applyBound(v int, relation ConstraintType, value float64) {
+	switch relation {
+	case LessEqual:
+		p.upper[v] = value
+	case GreaterEqual:
+		p.lower[v] = value
+	case Equal:
+		p.lower[v] = value
+		p.upper[v] = value
+	}
+}
+
+func reverseRelation(r ConstraintType) ConstraintType {
+	switch r {
+	case LessEqual:
+		return GreaterEqual
+	case GreaterEqual:
+		return LessEqual
+	}
+	return r
+}
+
+func (p *lpParser) parseVarList(binary bool) error {
+	for t := p.peek(0); t != nil; t = p.peek(0) {
+		if t.kind != lpName {
+			return p.errorf("expected a variable name")
+		}
+		p.pos++
+		v := p.variable(t.text)
+		if binary {
+			p.lower[v] = 0
+			p.upper[v] = 1
+		}
+	}
+	return nil
+}
+
+// variable gets the index of a variable, adding it with
+// the default bounds if it is new.
+func (p *lpParser) variable(name string) int {
+	if idx, ok := p.varIndex[name]; ok {
+		return idx
+	}
+	idx := len(p.varNames)
+	p.varIndex[name] = idx
+	p.varNames = append(p.varNames, name)
+	p.lower = append(p.lower, 0)
+	p.upper = append(p.upper, math.Inf(1))
+	return idx
+}
+
+func (p *lpParser) model() (*Model, error) {
+	m := NewModel()
+	vars := make([]Var, len(p.varNames))
+	for i, name := range p.varNames {
+		lower, upper := p.lower[i], p.upper[i]
+		if !(lower <= upper) || math.IsInf(lower, 1) || math.IsInf(upper, -1) {
+			return nil, fmt.Errorf("lp: invalid bounds for variable %q: [%g, %g]", name,
+				lower, upper)
+		}
+		vars[i] = m.AddVar(name, lower, upper)
+	}
+	expr := func(terms []lpTerm, constant float64) Expr {
+		res := Const(constant)
+		for _, t := range terms {
+			res.Terms = append(res.Terms, Term{Coeff: t.coeff, Var: vars[p.varIndex[t.name]]})
+		}
+		return res
+	}
+	if p.minimize {
+		m.Minimize(expr(p.objective, p.objConstant))
+	} else {
+		m.Maximize(expr(p.objective, p.objConstant))
+	}
+	for _, c := range p.constraints {
+		if c.ranged {
+			m.AddRangeConstraint(c.name, expr(c.terms, c.constant), c.lower, c.rhs)
+		} else {
+			m.AddConstraint(c.name, expr(c.terms, c.constant), c.relation, c.rhs)
+		}
+	}
+	return m, nil
+}
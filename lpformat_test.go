@@ -0,0 +1,155 @@
+package linprog
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testLPFormat = `\ A hand-written model.
+Maximize
+ profit: 3x + 2 y - z
+   + 0.5 w + 1
+Subject To
+ labor: x + y + z <= 10
+ - x + 2 y >= -4
+ mix: x - w = 0
+ cap: 2 x + y + 1 <= 13
+Bounds
+ y <= 4
+ -1 <= z <= 5
+ w >= -2
+ 5 >= v
+General
+ y
+Binary
+ b
+End
+`
+
+func TestReadLP(t *testing.T) {
+	model, err := ReadLP(strings.NewReader(testLPFormat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inf := math.Inf(1)
+	expected := &GeneralLP{
+		Objective: Vector{3, 2, -1, 0.5, 0, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 4,
+			NumCols: 6,
+			Data: []float64{
+				1, 1, 1, 0, 0, 0,
+				-1, 2, 0, 0, 0, 0,
+				1, 0, 0, -1, 0, 0,
+				2, 1, 0, 0, 0, 0,
+			},
+		},
+		ConstraintVector: Vector{10, -4, 0, 12},
+		ConstraintTypes:  []ConstraintType{LessEqual, GreaterEqual, Equal, LessEqual},
+		LowerBounds:      Vector{0, 0, -1, -2, 0, 0},
+		UpperBounds:      Vector{inf, 4, 5, inf, 5, 1},
+	}
+	if actual := model.GeneralLP(); !generalLPsEqual(actual, expected) {
+		t.Errorf("unexpected program: %+v", actual)
+	}
+	for i, name := range []string{"x", "y", "z", "w", "v", "b"} {
+		if v, ok := model.Var(name); !ok || v.index != i {
+			t.Errorf("variable %s: expected index %d", name, i)
+		}
+	}
+	for i, name := range []string{"labor", "R2", "mix", "cap"} {
+		if row, ok := model.nameToRow[name]; !ok || row != i {
+			t.Errorf("constraint %s: expected row %d", name, i)
+		}
+	}
+
+	solution := model.Solve(BlandPivotRule{}, false)
+	if solution.Status() != Optimal {
+		t.Fatalf("unexpected status: %v", solution.Status())
+	}
+	if math.Abs(solution.Objective-24) > 1e-8 {
+		t.Errorf("unexpected objective: %f", solution.Objective)
+	}
+}
+
+func TestReadLPErrors(t *testing.T) {
+	inputs := []string{
+		"Subject To\n c: x <= 1\nEnd\n",
+		"Maximize\n x\nSubject To\n c: x + y\nEnd\n",
+		"Maximize\n x\nSubject To\n c: x <= 1\n c: y <= 1\nEnd\n",
+		"Maximize\n x\nBounds\n x <=\nEnd\n",
+		"Maximize\n x ^ 2\nEnd\n",
+		"Maximize\n x\nSemi-Continuous\n x\nEnd\n",
+		"Maximize\n x\n",
+		"Maximize\n x\nBounds\n x <= -1\nEnd\n",
+		"Maximize\n x\nSubject To\n c: 1 <= x >= 2\nEnd\n",
+		"Maximize\n x\nSubject To\n c: 3 <= x <= 2\nEnd\n",
+	}
+	for i, input := range inputs {
+		if _, err := ReadLP(strings.NewReader(input)); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestWriteLP(t *testing.T) {
+	model := NewModel()
+	var vars []Var
+	for i := 0; i < 10; i++ {
+		vars = append(vars, model.AddNonNegVar(fmt.Sprintf("x_%d", i)))
+	}
+	vars = append(vars,
+		model.AddVar("unbounded", math.Inf(-1), math.Inf(1)),
+		model.AddVar("neg", math.Inf(-1), -1),
+		model.AddVar("fixed", 2.5, 2.5),
+		model.AddVar("below", -5, -1),
+		model.AddVar("boxed", -3, 7),
+		model.AddVar("lower", 1e-7, math.Inf(1)),
+	)
+	var objective Expr
+	for i, v := range vars {
+		objective = objective.Add(v.Mul(float64(i) - 4.5))
+	}
+	objective = objective.Add(vars[0].Mul(1)).Add(Const(-2))
+	model.Minimize(objective)
+	model.AddConstraint("all", Sum(objective, vars[3].Mul(-1)), LessEqual, 100)
+	model.AddConstraint("pair", Sum(vars[1].Mul(-1), vars[2].Mul(1/3.0), Const(1)), Equal, 0)
+	model.AddConstraint("empty", Expr{}, GreaterEqual, -1)
+	model.AddRangeConstraint("range", Sum(vars[4].Mul(2), vars[5].Mul(-1), Const(3)), -1, 6)
+
+	var buf bytes.Buffer
+	if err := WriteLP(&buf, model); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := ReadLP(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every variable appears in the objective, so the
+	// variables are read back in the same order.
+	if !generalLPsEqual(actual.GeneralLP(), model.GeneralLP()) {
+		t.Errorf("round trip changed the program:\n%s", buf.String())
+	}
+	if actual.objective.Constant != -2 {
+		t.Errorf("unexpected objective constant: %f", actual.objective.Constant)
+	}
+	for i, v := range model.vars {
+		if actual.vars[i].name != v.name {
+			t.Errorf("variable %d: expected name %s but got %s", i, v.name, actual.vars[i].name)
+		}
+	}
+	for i, c := range model.constraints {
+		if actual.constraints[i].name != c.name {
+			t.Errorf("constraint %d: expected name %s but got %s", i, c.name,
+				actual.constraints[i].name)
+		}
+	}
+
+	model.AddNonNegVar("x[1]")
+	if err := WriteLP(&bytes.Buffer{}, model); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+}
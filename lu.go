@@ -0,0 +1,122 @@
+package linprog
+
+import "math"
+
+// An LU is an LU factorization of a square matrix with
+// partial pivoting.
+// In particular, it represents P*A = L*U, where P is a
+// permutation matrix, L is unit lower triangular, and U
+// is upper triangular.
+type LU struct {
+	size int
+
+	// data stores L below the diagonal and U on and above
+	// the diagonal, in row-major order.
+	data []float64
+
+	// perm maps rows of L*U to rows of the original
+	// matrix.
+	perm []int
+}
+
+// NewLU factorizes a square matrix.
+//
+// If the matrix is numerically singular, nil is
+// returned.
+func NewLU(m Matrix) *LU {
+	size := m.Rows()
+	if m.Cols() != size {
+		panic("matrix must be square")
+	}
+	res := &LU{
+		size: size,
+		data: make([]float64, size*size),
+		perm: make([]int, size),
+	}
+	for i := 0; i < size; i++ {
+		copy(res.data[i*size:(i+1)*size], m.CopyRow(i))
+		res.perm[i] = i
+	}
+	epsilon := m.AbsMax() * relativeEpsilon
+	for col := 0; col < size; col++ {
+		pivotRow := col
+		pivotAbs := 0.0
+		for row := col; row < size; row++ {
+			if abs := math.Abs(res.at(row, col)); abs > pivotAbs {
+				pivotRow = row
+				pivotAbs = abs
+			}
+		}
+		if pivotAbs <= epsilon {
+			return nil
+		}
+		res.swapRows(col, pivotRow)
+		pivot := res.at(col, col)
+		for row := col + 1; row < size; row++ {
+			scale := res.at(row, col) / pivot
+			if scale == 0 {
+				continue
+			}
+			res.data[row*size+col] = scale
+			for k := col + 1; k < size; k++ {
+				res.data[row*size+k] -= scale * res.at(col, k)
+			}
+		}
+	}
+	return res
+}
+
+// Solve solves A*x = b for x.
+func (l *LU) Solve(b Vector) Vector {
+	x := make(Vector, l.size)
+	for i, row := range l.perm {
+		x[i] = b[row]
+	}
+	for i := 0; i < l.size; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= l.at(i, j) * x[j]
+		}
+	}
+	for i := l.size - 1; i >= 0; i-- {
+		for j := i + 1; j < l.size; j++ {
+			x[i] -= l.at(i, j) * x[j]
+		}
+		x[i] /= l.at(i, i)
+	}
+	return x
+}
+
+// SolveTranspose solves A'*x = b for x.
+func (l *LU) SolveTranspose(b Vector) Vector {
+	z := append(Vector{}, b...)
+	for i := 0; i < l.size; i++ {
+		for j := 0; j < i; j++ {
+			z[i] -= l.at(j, i) * z[j]
+		}
+		z[i] /= l.at(i, i)
+	}
+	for i := l.size - 1; i >= 0; i-- {
+		for j := i + 1; j < l.size; j++ {
+			z[i] -= l.at(j, i) * z[j]
+		}
+	}
+	x := make(Vector, l.size)
+	for i, row := range l.perm {
+		x[row] = z[i]
+	}
+	return x
+}
+
+func (l *LU) at(i, j int) float64 {
+	return l.data[i*l.size+j]
+}
+
+func (l *LU) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	l.perm[i], l.perm[j] = l.perm[j], l.perm[i]
+	for k := 0; k < l.size; k++ {
+		l.data[i*l.size+k], l.data[j*l.size+k] = l.data[j*l.size+k], l.data[i*l.size+k]
+	}
+}
@@ -0,0 +1,407 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Var is a handle to a variable in a Model.
+type Var struct {
+	index int
+}
+
+// Mul creates the expression coeff*v.
+func (v Var) Mul(coeff float64) Expr {
+	return Expr{Terms: []Term{{Coeff: coeff, Var: v}}}
+}
+
+// Expr creates the expression 1*v.
+func (v Var) Expr() Expr {
+	return v.Mul(1)
+}
+
+// A Term is a variable multiplied by a coefficient.
+type Term struct {
+	Coeff float64
+	Var   Var
+}
+
+// An Expr is a linear expression of variables plus a
+// constant.
+//
+// A variable may appear in more than one term, in which
+// case the coefficients are summed.
+type Expr struct {
+	Terms    []Term
+	Constant float64
+}
+
+// Const creates a constant expression.
+func Const(c float64) Expr {
+	return Expr{Constant: c}
+}
+
+// Sum adds expressions together.
+func Sum(exprs ...Expr) Expr {
+	var res Expr
+	for _, e := range exprs {
+		res = res.Add(e)
+	}
+	return res
+}
+
+// Add computes e + other.
+func (e Expr) Add(other Expr) Expr {
+	return Expr{
+		Terms:    append(append([]Term{}, e.Terms...), other.Terms...),
+		Constant: e.Constant + other.Constant,
+	}
+}
+
+// Scale computes s*e.
+func (e Expr) Scale(s float64) Expr {
+	res := Expr{
+		Terms:    make([]Term, len(e.Terms)),
+		Constant: e.Constant * s,
+	}
+	for i, t := range e.Terms {
+		res.Terms[i] = Term{Coeff: t.Coeff * s, Var: t.Var}
+	}
+	return res
+}
+
+// Eval evaluates the expression given a value for every
+// variable, indexed by the variables' handles.
+func (e Expr) Eval(values Vector) float64 {
+	res := e.Constant
+	for _, t := range e.Terms {
+		res += t.Coeff * values[t.Var.index]
+	}
+	return res
+}
+
+type modelVar struct {
+	name  string
+	lower float64
+	upper float64
+}
+
+type modelConstraint struct {
+	name     string
+	expr     Expr
+	relation ConstraintType
+	rhs      float64
+
+	// ranged indicates a constraint rhs <= expr <= upper,
+	// in which case relation is unused.
+	ranged bool
+	upper  float64
+}
+
+// A Model is a builder for linear programs in which
+// variables and constraints are referred to by handles
+// and names rather than by indices.
+type Model struct {
+	minimize    bool
+	objective   Expr
+	vars        []modelVar
+	constraints []modelConstraint
+	nameToVar   map[string]Var
+	nameToRow   map[string]int
+}
+
+// NewModel creates an empty model with a zero objective.
+func NewModel() *Model {
+	return &Model{
+		nameToVar: map[string]Var{},
+		nameToRow: map[string]int{},
+	}
+}
+
+// AddVar adds a variable with the given bounds.
+// Bounds may be infinite, but lower may not exceed upper.
+//
+// Variable names must be unique.
+func (m *Model) AddVar(name string, lower, upper float64) Var {
+	if _, ok := m.nameToVar[name]; ok {
+		panic(fmt.Sprintf("duplicate variable name: %s", name))
+	}
+	if !(lower <= upper) || math.IsInf(lower, 1) || math.IsInf(upper, -1) {
+		panic(fmt.Sprintf("invalid bounds for variable %s: [%g, %g]", name, lower, upper))
+	}
+	v := Var{index: len(m.vars)}
+	m.vars = append(m.vars, modelVar{name: name, lower: lower, upper: upper})
+	m.nameToVar[name] = v
+	return v
+}
+
+// AddNonNegVar adds a variable which is bounded below by
+// zero and unbounded above.
+func (m *Model) AddNonNegVar(name string) Var {
+	return m.AddVar(name, 0, math.Inf(1))
+}
+
+// Var looks up a variable by name.
+func (m *Model) Var(name string) (Var, bool) {
+	v, ok := m.nameToVar[name]
+	return v, ok
+}
+
+// VarName gets the name of a variable.
+func (m *Model) VarName(v Var) string {
+	return m.vars[v.index].name
+}
+
+// NumVars gets the number of variables in the model.
+func (m *Model) NumVars() int {
+	return len(m.vars)
+}
+
+// Vars gets the variables in the order in which they were
+// added.
+func (m *Model) Vars() []Var {
+	res := make([]Var, len(m.vars))
+	for i := range res {
+		res[i] = Var{index: i}
+	}
+	return res
+}
+
+// AddConstraint adds the constraint lhs (relation) rhs.
+// Any constant in lhs is moved to the right-hand side.
+//
+// Constraint names must be unique.
+func (m *Model) AddConstraint(name string, lhs Expr, relation ConstraintType,
+	rhs float64) {
+	if _, ok := m.nameToRow[name]; ok {
+		panic(fmt.Sprintf("duplicate constraint name: %s", name))
+	}
+	m.nameToRow[name] = len(m.constraints)
+	m.constraints = append(m.constraints, modelConstraint{
+		name:     name,
+		expr:     lhs,
+		relation: relation,
+		rhs:      rhs,
+	})
+}
+
+// AddRangeConstraint adds the constraint
+// lower <= lhs <= upper. Any constant in lhs is moved to
+// both sides. The bounds must be finite, and lower may not
+// exceed upper.
+//
+// The constraint is compiled as a single equality row,
+// using an extra variable for lhs minus lower which is
+// bounded between 0 and upper-lower.
+//
+// Constraint names must be unique.
+func (m *Model) AddRangeConstraint(name string, lhs Expr, lower, upper float64) {
+	if !(lower <= upper) || math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+		panic(fmt.Sprintf("invalid range for constraint %s: [%g, %g]", name, lower, upper))
+	}
+	m.AddConstraint(name, lhs, Equal, lower)
+	c := &m.constraints[len(m.constraints)-1]
+	c.ranged = true
+	c.upper = upper
+}
+
+// NumConstraints gets the number of constraints in the
+// model.
+func (m *Model) NumConstraints() int {
+	return len(m.constraints)
+}
+
+// ConstraintNames gets the names of the constraints in the
+// order in which they were added.
+func (m *Model) ConstraintNames() []string {
+	res := make([]string, len(m.constraints))
+	for i, c := range m.constraints {
+		res[i] = c.name
+	}
+	return res
+}
+
+// Maximize sets the objective to maximize e.
+func (m *Model) Maximize(e Expr) {
+	m.minimize = false
+	m.objective = e
+}
+
+// Minimize sets the objective to minimize e.
+func (m *Model) Minimize(e Expr) {
+	m.minimize = true
+	m.objective = e
+}
+
+// GeneralLP compiles the model into a GeneralLP.
+// Variables and constraints are indexed in the order in
+// which they were added.
+// Each range constraint adds a variable for its
+// left-hand side minus its lower bound, and these
+// variables come after the model's own variables.
+//
+// Constant terms in the objective are dropped.
+func (m *Model) GeneralLP() *GeneralLP {
+	numVars := len(m.vars)
+	for _, c := range m.constraints {
+		if c.ranged {
+			numVars++
+		}
+	}
+	matrix := NewSparseMatrix(len(m.constraints), numVars)
+	vector := make(Vector, len(m.constraints))
+	types := make([]ConstraintType, len(m.constraints))
+	lower := make(Vector, numVars)
+	upper := make(Vector, numVars)
+	for i, v := range m.vars {
+		lower[i] = v.lower
+		upper[i] = v.upper
+	}
+	rangeVar := len(m.vars)
+	for i, c := range m.constraints {
+		for _, t := range c.expr.Terms {
+			matrix.Set(i, t.Var.index, matrix.At(i, t.Var.index)+t.Coeff)
+		}
+		vector[i] = c.rhs - c.expr.Constant
+		types[i] = c.relation
+		if c.ranged {
+			matrix.Set(i, rangeVar, -1)
+			upper[rangeVar] = c.upper - c.rhs
+			rangeVar++
+		}
+	}
+	objective := make(Vector, numVars)
+	for _, t := range m.objective.Terms {
+		objective[t.Var.index] += t.Coeff
+	}
+	return &GeneralLP{
+		Minimize:         m.minimize,
+		Objective:        objective,
+		ConstraintMatrix: matrix,
+		ConstraintVector: vector,
+		ConstraintTypes:  types,
+		LowerBounds:      lower,
+		UpperBounds:      upper,
+	}
+}
+
+// Compile compiles the model into a StandardLP.
+//
+// The mapping can be passed to Solution to interpret the
+// solution of the resulting program.
+func (m *Model) Compile() (*StandardLP, *StandardMapping) {
+	return m.GeneralLP().Standardize()
+}
+
+// Solve compiles the model and runs the simplex method.
+func (m *Model) Solve(pr PivotRule, dense bool) *ModelSolution {
+	lp, mapping := m.Compile()
+	return m.Solution(mapping, Simplex(lp, pr, dense))
+}
+
+// Solution converts the result of solving the compiled
+// program into a ModelSolution.
+func (m *Model) Solution(mapping *StandardMapping, res *Result) *ModelSolution {
+	solution := &ModelSolution{Model: m, Result: res}
+	if res.Solution != nil {
+		solution.Values = mapping.Solution(res.Solution)[:len(m.vars)]
+		solution.Objective = m.objective.Eval(solution.Values)
+	}
+	if res.Duals != nil {
+		solution.Duals = mapping.Duals(res.Duals)
+	}
+	if res.ReducedCosts != nil {
+		solution.ReducedCosts = mapping.ReducedCosts(res.ReducedCosts)[:len(m.vars)]
+	}
+	return solution
+}
+
+// A ModelSolution is a solution to a Model.
+type ModelSolution struct {
+	Model *Model
+
+	// Result is the result for the compiled program.
+	Result *Result
+
+	// Values stores the value of each variable, in the
+	// order the variables were added.
+	// It is nil if Result has no solution.
+	Values Vector
+
+	// Objective is the value of the objective, including
+	// any constant term.
+	Objective float64
+
+	// Duals stores the dual value of each constraint and
+	// ReducedCosts stores the reduced cost of each
+	// variable, in the order they were added.
+	// They are nil if Result has no dual information.
+	Duals        Vector
+	ReducedCosts Vector
+}
+
+// Status gets the status of the solve.
+func (m *ModelSolution) Status() SimplexStatus {
+	return m.Result.Status
+}
+
+// Value gets the value of a variable.
+func (m *ModelSolution) Value(v Var) float64 {
+	return m.Values[v.index]
+}
+
+// Dual gets the dual value (shadow price) of the named
+// constraint.
+func (m *ModelSolution) Dual(name string) float64 {
+	return m.Duals[m.Model.constraintIndex(name)]
+}
+
+// ReducedCost gets the reduced cost of a variable.
+func (m *ModelSolution) ReducedCost(v Var) float64 {
+	return m.ReducedCosts[v.index]
+}
+
+// Eval evaluates an expression at the solution.
+func (m *ModelSolution) Eval(e Expr) float64 {
+	return e.Eval(m.Values)
+}
+
+// Activity gets the value of the left-hand side of the
+// named constraint.
+func (m *ModelSolution) Activity(name string) float64 {
+	return m.Eval(m.Model.constraint(name).expr)
+}
+
+// Slack gets the amount by which the named constraint is
+// satisfied: the right-hand side minus the left-hand side
+// for <= constraints and the opposite for >= constraints.
+// For range constraints, it is the distance to the
+// nearest bound. For equality constraints, the slack is
+// zero.
+func (m *ModelSolution) Slack(name string) float64 {
+	c := m.Model.constraint(name)
+	diff := c.rhs - m.Eval(c.expr)
+	if c.ranged {
+		return math.Min(-diff, c.upper-m.Eval(c.expr))
+	}
+	switch c.relation {
+	case LessEqual:
+		return diff
+	case GreaterEqual:
+		return -diff
+	default:
+		return 0
+	}
+}
+
+func (m *Model) constraint(name string) *modelConstraint {
+	return &m.constraints[m.constraintIndex(name)]
+}
+
+func (m *Model) constraintIndex(name string) int {
+	row, ok := m.nameToRow[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown constraint: %s", name))
+	}
+	return row
+}
@@ -0,0 +1,67 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModel(t *testing.T) {
+	// Maximize 3x + 5y subject to
+	//     x <= 4
+	//     2y <= 12
+	//     3x + 2y <= 18
+	m := NewModel()
+	x := m.AddNonNegVar("x")
+	y := m.AddNonNegVar("y")
+	m.Maximize(Sum(x.Mul(3), y.Mul(5)))
+	m.AddConstraint("plant1", x.Expr(), LessEqual, 4)
+	m.AddConstraint("plant2", y.Mul(2), LessEqual, 12)
+	m.AddConstraint("plant3", Sum(x.Mul(3), y.Mul(2)), LessEqual, 18)
+
+	solution := m.Solve(BlandPivotRule{}, false)
+	if solution.Status() != Optimal {
+		t.Fatalf("unexpected status: %v", solution.Status())
+	}
+	if math.Abs(solution.Value(x)-2) > 1e-5 || math.Abs(solution.Value(y)-6) > 1e-5 {
+		t.Errorf("unexpected solution: %v", solution.Values)
+	}
+	if math.Abs(solution.Objective-36) > 1e-5 {
+		t.Errorf("unexpected objective: %f", solution.Objective)
+	}
+	if math.Abs(solution.Activity("plant3")-18) > 1e-5 {
+		t.Errorf("unexpected activity: %f", solution.Activity("plant3"))
+	}
+	if math.Abs(solution.Slack("plant1")-2) > 1e-5 {
+		t.Errorf("unexpected slack: %f", solution.Slack("plant1"))
+	}
+}
+
+func TestModelRangeConstraint(t *testing.T) {
+	// Maximize x + y subject to 1 <= x + 2y <= 4 and
+	// x <= 3, whose optimum is x = 3, y = 0.5.
+	m := NewModel()
+	x := m.AddVar("x", 0, 3)
+	y := m.AddNonNegVar("y")
+	m.Maximize(Sum(x.Expr(), y.Expr()))
+	m.AddRangeConstraint("range", Sum(x.Expr(), y.Mul(2), Const(1)), 2, 5)
+
+	if n := m.GeneralLP().Dim(); n != 3 {
+		t.Fatalf("expected 3 columns but got %d", n)
+	}
+	solution := m.Solve(BlandPivotRule{}, false)
+	if solution.Status() != Optimal {
+		t.Fatalf("unexpected status: %v", solution.Status())
+	}
+	if len(solution.Values) != 2 || len(solution.ReducedCosts) != 2 {
+		t.Fatalf("unexpected solution: %v", solution.Values)
+	}
+	if math.Abs(solution.Value(x)-3) > 1e-5 || math.Abs(solution.Value(y)-0.5) > 1e-5 {
+		t.Errorf("unexpected solution: %v", solution.Values)
+	}
+	if math.Abs(solution.Dual("range")-0.5) > 1e-5 {
+		t.Errorf("unexpected dual: %f", solution.Dual("range"))
+	}
+	if math.Abs(solution.Slack("range")) > 1e-5 {
+		t.Errorf("unexpected slack: %f", solution.Slack("range"))
+	}
+}
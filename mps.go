@@ -0,0 +1,468 @@
+package linprog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// mpsFixedFields stores the 1-based column ranges of the
+// six fields of a fixed MPS data line.
+var mpsFixedFields = [6][2]int{{2, 3}, {5, 12}, {15, 22}, {25, 36}, {40, 47}, {50, 61}}
+
+// ReadMPS reads a linear program in fixed MPS format.
+//
+// In fixed MPS, each field of a data line occupies a set
+// range of columns, so names may contain spaces.
+// See ReadFreeMPS for details on how the program is
+// translated into a Model.
+func ReadMPS(r io.Reader) (*Model, error) {
+	return readMPS(r, false)
+}
+
+// ReadFreeMPS reads a linear program in free MPS format.
+//
+// In free MPS, fields are separated by whitespace, and
+// the set names in the RHS, RANGES, and BOUNDS sections
+// may be omitted.
+//
+// Constraints and variables are added to the model in
+// the order in which they first appear, under their names
+// from the file. The first N row is the objective, and
+// other N rows are ignored.
+// The objective is minimized unless an OBJSENSE section
+// says otherwise.
+//
+// Each ranged row becomes a single range constraint; see
+// Model.AddRangeConstraint.
+//
+// Only the first set of each of the RHS, RANGES, and
+// BOUNDS sections is used. Integrality markers are
+// ignored, and constant terms in the objective are
+// dropped.
+func ReadFreeMPS(r io.Reader) (*Model, error) {
+	return readMPS(r, true)
+}
+
+// WriteMPS writes a linear program in free MPS format,
+// which ReadFreeMPS can read back.
+//
+// Constraints are named R0, R1, ..., variables are named
+// C0, C1, ..., and the objective is named OBJ.
+// Since the program is a maximization, the file has an
+// OBJSENSE section.
+func WriteMPS(w io.Writer, lp *StandardLP) error {
+	buf := bufio.NewWriter(w)
+	fmt.Fprintln(buf, "NAME")
+	fmt.Fprintln(buf, "OBJSENSE")
+	fmt.Fprintln(buf, "    MAX")
+	fmt.Fprintln(buf, "ROWS")
+	fmt.Fprintln(buf, " N  OBJ")
+	for i := range lp.ConstraintVector {
+		fmt.Fprintf(buf, " E  R%d\n", i)
+	}
+	fmt.Fprintln(buf, "COLUMNS")
+	for j := 0; j < lp.Dim(); j++ {
+		c := lp.Objective[j]
+		column := lp.ConstraintMatrix.CopyCol(j)
+		if c != 0 || column.AbsMax() == 0 {
+			fmt.Fprintf(buf, "    C%d  OBJ  %s\n", j, formatMPSNumber(c))
+		}
+		for i, x := range column {
+			if x != 0 {
+				fmt.Fprintf(buf, "    C%d  R%d  %s\n", j, i, formatMPSNumber(x))
+			}
+		}
+	}
+	fmt.Fprintln(buf, "RHS")
+	for i, b := range lp.ConstraintVector {
+		if b != 0 {
+			fmt.Fprintf(buf, "    RHS  R%d  %s\n", i, formatMPSNumber(b))
+		}
+	}
+	if lp.hasUpperBounds() {
+		fmt.Fprintln(buf, "BOUNDS")
+		for j, u := range lp.UpperBounds {
+			if !math.IsInf(u, 1) {
+				fmt.Fprintf(buf, " UP BND  C%d  %s\n", j, formatMPSNumber(u))
+			}
+		}
+	}
+	fmt.Fprintln(buf, "ENDATA")
+	return buf.Flush()
+}
+
+func formatMPSNumber(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// mpsReader accumulates the contents of an MPS file.
+type mpsReader struct {
+	free bool
+	line int
+
+	objective string
+	minimize  bool
+
+	rowNames  []string
+	rowTypes  map[string]byte
+	rowIndex  map[string]int
+	colNames  []string
+	colIndex  map[string]int
+	entries   []map[int]float64
+	objCoeffs map[int]float64
+
+	rhs    map[int]float64
+	ranges map[int]float64
+	lower  map[int]float64
+	upper  map[int]float64
+
+	// The first set name seen in each section, or nil if
+	// none has been seen yet.
+	rhsSet   *string
+	rangeSet *string
+	boundSet *string
+}
+
+func readMPS(r io.Reader, free bool) (*Model, error) {
+	m := &mpsReader{
+		free:      free,
+		minimize:  true,
+		rowTypes:  map[string]byte{},
+		rowIndex:  map[string]int{},
+		colIndex:  map[string]int{},
+		objCoeffs: map[int]float64{},
+		rhs:       map[int]float64{},
+		ranges:    map[int]float64{},
+		lower:     map[int]float64{},
+		upper:     map[int]float64{},
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	section := ""
+	finished := false
+	for scanner.Scan() && !finished {
+		m.line++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || line[0] == '*' {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			fields := strings.Fields(line)
+			section = fields[0]
+			switch section {
+			case "NAME", "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS":
+			case "OBJSENSE":
+				if len(fields) > 1 {
+					if err := m.objSense(fields[1]); err != nil {
+						return nil, err
+					}
+				}
+			case "ENDATA":
+				finished = true
+			default:
+				return nil, m.errorf("unknown section %q", section)
+			}
+			continue
+		}
+		if err := m.dataLine(section, line); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if m.objective == "" {
+		return nil, fmt.Errorf("mps: missing objective row")
+	}
+	return m.model()
+}
+
+func (m *mpsReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("mps: line %d: %s", m.line, fmt.Sprintf(format, args...))
+}
+
+func (m *mpsReader) objSense(sense string) error {
+	switch sense {
+	case "MAX", "MAXIMIZE":
+		m.minimize = false
+	case "MIN", "MINIMIZE":
+		m.minimize = true
+	default:
+		return m.errorf("unknown objective sense %q", sense)
+	}
+	return nil
+}
+
+// fields splits a data line into the six fields of the
+// fixed format, which are empty if they are missing.
+//
+// For free MPS, the fields are assigned to the same
+// positions, accounting for omitted set names.
+func (m *mpsReader) fields(section, line string) [6]string {
+	var res [6]string
+	if !m.free {
+		for i, r := range mpsFixedFields {
+			if len(line) < r[0] {
+				break
+			}
+			end := r[1]
+			if len(line) < end {
+				end = len(line)
+			}
+			res[i] = strings.TrimSpace(line[r[0]-1 : end])
+		}
+		return res
+	}
+	fields := strings.Fields(line)
+	switch section {
+	case "ROWS":
+		copy(res[:2], fields)
+	case "COLUMNS":
+		copy(res[1:], fields)
+	case "RHS", "RANGES":
+		if len(fields)%2 == 0 {
+			copy(res[2:], fields)
+		} else {
+			copy(res[1:], fields)
+		}
+	case "BOUNDS":
+		res[0] = fields[0]
+		hasValue := true
+		switch fields[0] {
+		case "FR", "MI", "PL", "BV":
+			hasValue = len(fields) == 4
+		}
+		numFields := 3
+		if hasValue {
+			numFields = 4
+		}
+		if len(fields) >= numFields {
+			copy(res[1:], fields[1:])
+		} else {
+			copy(res[2:], fields[1:])
+		}
+	default:
+		copy(res[:], fields)
+	}
+	return res
+}
+
+func (m *mpsReader) dataLine(section, line string) error {
+	f := m.fields(section, line)
+	switch section {
+	case "NAME":
+	case "OBJSENSE":
+		return m.objSense(strings.TrimSpace(line))
+	case "ROWS":
+		return m.addRow(f[0], f[1])
+	case "COLUMNS":
+		if f[2] == "'MARKER'" {
+			return nil
+		}
+		if f[1] == "" {
+			return m.errorf("missing column name")
+		}
+		col, ok := m.colIndex[f[1]]
+		if !ok {
+			col = len(m.colNames)
+			m.colIndex[f[1]] = col
+			m.colNames = append(m.colNames, f[1])
+			m.entries = append(m.entries, map[int]float64{})
+		}
+		return m.pairs(f, func(row string, x float64) error {
+			if row == m.objective {
+				m.objCoeffs[col] = x
+			} else if idx, ok := m.rowIndex[row]; ok {
+				m.entries[col][idx] = x
+			} else if _, ok := m.rowTypes[row]; !ok {
+				return m.errorf("unknown row %q", row)
+			}
+			return nil
+		})
+	case "RHS", "RANGES":
+		set := &m.rhsSet
+		values := m.rhs
+		if section == "RANGES" {
+			set = &m.rangeSet
+			values = m.ranges
+		}
+		if *set == nil {
+			*set = &f[1]
+		} else if **set != f[1] {
+			return nil
+		}
+		return m.pairs(f, func(row string, x float64) error {
+			if idx, ok := m.rowIndex[row]; ok {
+				values[idx] = x
+			} else if _, ok := m.rowTypes[row]; !ok {
+				return m.errorf("unknown row %q", row)
+			}
+			return nil
+		})
+	case "BOUNDS":
+		if m.boundSet == nil {
+			m.boundSet = &f[1]
+		} else if *m.boundSet != f[1] {
+			return nil
+		}
+		return m.addBound(f[0], f[2], f[3])
+	default:
+		return m.errorf("data outside of a section")
+	}
+	return nil
+}
+
+func (m *mpsReader) addRow(rowType, name string) error {
+	if name == "" {
+		return m.errorf("missing row name")
+	}
+	if _, ok := m.rowTypes[name]; ok {
+		return m.errorf("duplicate row %q", name)
+	}
+	switch rowType {
+	case "N":
+		if m.objective == "" {
+			m.objective = name
+		}
+	case "E", "L", "G":
+		m.rowIndex[name] = len(m.rowNames)
+		m.rowNames = append(m.rowNames, name)
+	default:
+		return m.errorf("unknown row type %q", rowType)
+	}
+	m.rowTypes[name] = rowType[0]
+	return nil
+}
+
+// pairs calls f for each (row, value) pair in the fields
+// of a COLUMNS, RHS, or RANGES line.
+func (m *mpsReader) pairs(fields [6]string, f func(row string, x float64) error) error {
+	for i := 2; i < 6; i += 2 {
+		if fields[i] == "" {
+			if i == 2 {
+				return m.errorf("missing row name")
+			}
+			continue
+		}
+		x, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			return m.errorf("invalid number %q", fields[i+1])
+		}
+		if err := f(fields[i], x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mpsReader) addBound(boundType, colName, value string) error {
+	col, ok := m.colIndex[colName]
+	if !ok {
+		return m.errorf("unknown column %q", colName)
+	}
+	var x float64
+	switch boundType {
+	case "UP", "LO", "FX", "LI", "UI":
+		var err error
+		x, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return m.errorf("invalid number %q", value)
+		}
+	}
+	switch boundType {
+	case "UP", "UI":
+		m.upper[col] = x
+		if lower, ok := m.lower[col]; x < 0 && (!ok || lower == 0) {
+			// By convention, a negative upper bound on a
+			// variable with the default lower bound makes
+			// the variable unbounded below.
+			m.lower[col] = math.Inf(-1)
+		}
+	case "LO", "LI":
+		m.lower[col] = x
+	case "FX":
+		m.lower[col] = x
+		m.upper[col] = x
+	case "FR":
+		m.lower[col] = math.Inf(-1)
+		m.upper[col] = math.Inf(1)
+	case "MI":
+		m.lower[col] = math.Inf(-1)
+	case "PL":
+		m.upper[col] = math.Inf(1)
+	case "BV":
+		m.lower[col] = 0
+		m.upper[col] = 1
+	default:
+		return m.errorf("unsupported bound type %q", boundType)
+	}
+	return nil
+}
+
+// model builds the program, with a range constraint for
+// each ranged row.
+func (m *mpsReader) model() (*Model, error) {
+	model := NewModel()
+	vars := make([]Var, len(m.colNames))
+	objective := Expr{}
+	for col, name := range m.colNames {
+		lower, upper := 0.0, math.Inf(1)
+		if x, ok := m.lower[col]; ok {
+			lower = x
+		}
+		if x, ok := m.upper[col]; ok {
+			upper = x
+		}
+		if !(lower <= upper) || math.IsInf(lower, 1) || math.IsInf(upper, -1) {
+			return nil, fmt.Errorf("mps: invalid bounds for column %q: [%g, %g]", name,
+				lower, upper)
+		}
+		vars[col] = model.AddVar(name, lower, upper)
+		if c, ok := m.objCoeffs[col]; ok {
+			objective = objective.Add(vars[col].Mul(c))
+		}
+	}
+	if m.minimize {
+		model.Minimize(objective)
+	} else {
+		model.Maximize(objective)
+	}
+
+	rows := make([]Expr, len(m.rowNames))
+	for col, entries := range m.entries {
+		for row, x := range entries {
+			rows[row].Terms = append(rows[row].Terms, Term{Coeff: x, Var: vars[col]})
+		}
+	}
+	for row, name := range m.rowNames {
+		rhs := m.rhs[row]
+		rowType := m.rowTypes[name]
+		r, ranged := m.ranges[row]
+		if !ranged || (r == 0 && rowType == 'E') {
+			relation := map[byte]ConstraintType{
+				'E': Equal,
+				'L': LessEqual,
+				'G': GreaterEqual,
+			}[rowType]
+			model.AddConstraint(name, rows[row], relation, rhs)
+			continue
+		}
+		lower, upper := rhs, rhs
+		switch {
+		case rowType == 'L' || (rowType == 'E' && r < 0):
+			lower -= math.Abs(r)
+		default:
+			upper += math.Abs(r)
+		}
+		if math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+			return nil, fmt.Errorf("mps: invalid range for row %q: [%g, %g]", name,
+				lower, upper)
+		}
+		model.AddRangeConstraint(name, rows[row], lower, upper)
+	}
+	return model, nil
+}
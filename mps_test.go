@@ -0,0 +1,223 @@
+package linprog
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testFixedMPS = `* A small program with every kind of row and bound.
+NAME          TESTPROB
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+ E  MYEQN
+ L  RNG ROW
+ N  UNUSED
+COLUMNS
+    XONE      COST                 1   LIM1                 1
+    XONE      LIM2                 1   RNG ROW              2
+    MARKER    'MARKER'                 'INTORG'
+    YTWO      COST                 2   LIM1                 1
+    YTWO      MYEQN               -1   UNUSED               5
+    MARKER    'MARKER'                 'INTEND'
+    ZTHREE    COST                 3   LIM2                 1
+    ZTHREE    MYEQN                1
+    W FOUR    COST                -1   RNG ROW              1
+RHS
+    RHS       COST               -10   LIM1                 4
+    RHS       LIM2                -1   MYEQN                7
+    RHS       RNG ROW              6
+    OTHER     LIM1               100
+RANGES
+    RNG       RNG ROW             -4
+BOUNDS
+ UP BND       XONE                 4
+ LO BND       YTWO                -1
+ UP BND       YTWO                 1
+ UP BND       W FOUR              -2
+ UP OTHER     ZTHREE               1
+ENDATA
+`
+
+const testFreeMPS = `NAME TESTPROB
+ROWS
+ N COST
+ L LIM1
+ G LIM2
+ E MYEQN
+ L RNGROW
+ N UNUSED
+COLUMNS
+ XONE COST 1 LIM1 1
+ XONE LIM2 1 RNGROW 2
+ MARKER 'MARKER' 'INTORG'
+ YTWO COST 2 LIM1 1
+ YTWO MYEQN -1 UNUSED 5
+ MARKER 'MARKER' 'INTEND'
+ ZTHREE COST 3 LIM2 1
+ ZTHREE MYEQN 1
+ WFOUR COST -1 RNGROW 1
+RHS
+ COST -10 LIM1 4
+ LIM2 -1 MYEQN 7
+ RNGROW 6
+RANGES
+ RNGROW -4
+BOUNDS
+ UP XONE 4
+ LO YTWO -1
+ UP YTWO 1
+ UP WFOUR -2
+ENDATA
+`
+
+func TestReadMPS(t *testing.T) {
+	// The ranged row is a single row, with an extra
+	// variable between 0 and the size of the range.
+	expected := &GeneralLP{
+		Minimize:  true,
+		Objective: Vector{1, 2, 3, -1, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 4,
+			NumCols: 5,
+			Data: []float64{
+				1, 1, 0, 0, 0,
+				1, 0, 1, 0, 0,
+				0, -1, 1, 0, 0,
+				2, 0, 0, 1, -1,
+			},
+		},
+		ConstraintVector: Vector{4, -1, 7, 2},
+		ConstraintTypes:  []ConstraintType{LessEqual, GreaterEqual, Equal, Equal},
+		LowerBounds:      Vector{0, -1, 0, math.Inf(-1), 0},
+		UpperBounds:      Vector{4, 1, math.Inf(1), -2, 4},
+	}
+	for _, free := range []bool{false, true} {
+		var model *Model
+		var err error
+		if free {
+			model, err = ReadFreeMPS(strings.NewReader(testFreeMPS))
+		} else {
+			model, err = ReadMPS(strings.NewReader(testFixedMPS))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !generalLPsEqual(model.GeneralLP(), expected) {
+			t.Errorf("free=%v: unexpected program: %+v", free, model.GeneralLP())
+		}
+		rangeName := "RNG ROW"
+		varNames := []string{"XONE", "YTWO", "ZTHREE", "W FOUR"}
+		if free {
+			rangeName = "RNGROW"
+			varNames[3] = "WFOUR"
+		}
+		for i, name := range varNames {
+			if v, ok := model.Var(name); !ok || v.index != i {
+				t.Errorf("free=%v: missing variable %q", free, name)
+			}
+		}
+		for i, name := range []string{"LIM1", "LIM2", "MYEQN", rangeName} {
+			if row, ok := model.nameToRow[name]; !ok || row != i {
+				t.Errorf("free=%v: missing constraint %q", free, name)
+			}
+		}
+
+		solution := model.Solve(BlandPivotRule{}, false)
+		if solution.Status() != Optimal {
+			t.Fatalf("free=%v: unexpected status: %v", free, solution.Status())
+		}
+		if math.Abs(solution.Objective-20) > 1e-8 {
+			t.Errorf("free=%v: unexpected objective: %f", free, solution.Objective)
+		}
+		if len(solution.Values) != len(varNames) || len(solution.Duals) != 4 {
+			t.Errorf("free=%v: unexpected solution size", free)
+		}
+		if activity := solution.Activity(rangeName); activity < 2-1e-8 || activity > 6+1e-8 {
+			t.Errorf("free=%v: range violated: %f", free, activity)
+		}
+	}
+}
+
+func TestReadMPSErrors(t *testing.T) {
+	inputs := []string{
+		"ROWS\n N COST\n L LIM1\nCOLUMNS\n X COST 1 LIM2 1\nENDATA\n",
+		"ROWS\n N COST\nCOLUMNS\n X COST abc\nENDATA\n",
+		"ROWS\n L LIM1\nENDATA\n",
+		"ROWS\n N COST\nCOLUMNS\n X COST 1\nBOUNDS\n SC BND X 1\nENDATA\n",
+		"ROWS\n N COST\nFOO\nENDATA\n",
+		"ROWS\n N COST\nCOLUMNS\n X COST 1\nBOUNDS\n LO BND X 2\n UP BND X 1\nENDATA\n",
+	}
+	for i, input := range inputs {
+		if _, err := ReadFreeMPS(strings.NewReader(input)); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestWriteMPS(t *testing.T) {
+	problem := randomStandardLP(5, 8)
+	problem.UpperBounds = make(Vector, problem.Dim())
+	for i := range problem.UpperBounds {
+		problem.UpperBounds[i] = math.Inf(1)
+	}
+	problem.UpperBounds[2] = 0.5
+	problem.UpperBounds[5] = 3
+	problem.Objective[3] = 0
+	problem.ConstraintVector[1] = 0
+	for i := 0; i < problem.ConstraintMatrix.Rows(); i++ {
+		problem.ConstraintMatrix.Set(i, 6, 0)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMPS(&buf, problem); err != nil {
+		t.Fatal(err)
+	}
+	model, err := ReadFreeMPS(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := model.GeneralLP()
+	expected := &GeneralLP{
+		Objective:        problem.Objective,
+		ConstraintMatrix: problem.ConstraintMatrix,
+		ConstraintVector: problem.ConstraintVector,
+		ConstraintTypes:  make([]ConstraintType, len(problem.ConstraintVector)),
+		LowerBounds:      make(Vector, problem.Dim()),
+		UpperBounds:      problem.UpperBounds,
+	}
+	if !generalLPsEqual(actual, expected) {
+		t.Errorf("round trip changed the program: %+v", actual)
+	}
+}
+
+// generalLPsEqual checks if two programs are exactly
+// equal, treating nil fields as their defaults.
+func generalLPsEqual(g1, g2 *GeneralLP) bool {
+	if g1.Minimize != g2.Minimize || g1.Dim() != g2.Dim() ||
+		len(g1.ConstraintVector) != len(g2.ConstraintVector) {
+		return false
+	}
+	for i := 0; i < g1.Dim(); i++ {
+		if g1.Objective[i] != g2.Objective[i] || g1.LowerBound(i) != g2.LowerBound(i) ||
+			g1.UpperBound(i) != g2.UpperBound(i) {
+			return false
+		}
+	}
+	for i, b := range g1.ConstraintVector {
+		if b != g2.ConstraintVector[i] || g1.ConstraintType(i) != g2.ConstraintType(i) {
+			return false
+		}
+		row1 := g1.ConstraintMatrix.CopyRow(i)
+		row2 := g2.ConstraintMatrix.CopyRow(i)
+		for j, x := range row1 {
+			if x != row2[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
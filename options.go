@@ -0,0 +1,354 @@
+package linprog
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Options configures SimplexWithOptions and
+// SimplexPhase1WithOptions.
+// The zero value gives the behavior of Simplex and
+// SimplexPhase1.
+type Options struct {
+	// Perturbation is the relative size of a random
+	// perturbation added to the right-hand side before
+	// solving, or 0 to disable perturbation.
+	//
+	// Perturbation makes degenerate vertices unlikely, so
+	// that entering rules such as GreedyPivotRule are
+	// unlikely to stall or cycle. Once the perturbed
+	// program is solved, the original right-hand side is
+	// restored and the dual simplex method removes any
+	// resulting infeasibility.
+	// If the perturbed program cannot be solved to
+	// optimality, the original program is solved without
+	// perturbation instead, and the result's Perturbed
+	// field is false.
+	//
+	// Upper bounds are not perturbed.
+	// A typical value is 1e-6.
+	Perturbation float64
+
+	// Context, if non-nil, stops the algorithm with a
+	// Cancelled status once it is done.
+	Context context.Context
+
+	// MaxIterations, if positive, limits the number of
+	// pivots across both phases, including dual simplex
+	// pivots. The algorithm stops with an IterationLimit
+	// status when it is reached.
+	//
+	// If a perturbed program cannot be solved, the pivots
+	// spent on it still count towards the limit, and they
+	// are included in the result's Phase1Iterations.
+	MaxIterations int
+
+	// Deadline, if non-zero, is the time after which the
+	// algorithm stops with an IterationLimit status.
+	Deadline time.Time
+
+	// Progress, if non-nil, is called after each pivot,
+	// including dual simplex pivots. See NewProgressLogger
+	// for a ready-made implementation.
+	Progress func(p Progress)
+}
+
+// SimplexWithOptions is like Simplex, but with extra
+// options. If opts is nil, default options are used.
+//
+// If the algorithm is stopped early, the result includes
+// the last feasible point, if one was found.
+func SimplexWithOptions(lp *StandardLP, pr PivotRule, dense bool, opts *Options) *Result {
+	if opts == nil {
+		opts = &Options{}
+	}
+	pr = newOptionsPivotRule(pr, opts)
+	var spent int
+	if opts.Perturbation != 0 {
+		res, ok := perturbedSimplex(lp, pr, dense, opts.Perturbation)
+		if ok {
+			return res
+		}
+		spent = res.Phase1Iterations + res.Phase2Iterations
+		dropPendingProgress(pr)
+	}
+	_, res := SimplexWithTableau(lp, pr, dense)
+	res.Phase1Iterations += spent
+	return res
+}
+
+// SimplexPhase1WithOptions is like SimplexPhase1, but with
+// extra options. If opts is nil, default options are used.
+//
+// With perturbation, phase 1 is solved for the perturbed
+// right-hand side, and the dual simplex method then makes
+// the phase 1 basis feasible for the original one.
+//
+// The result records the phase 1 iterations and whether
+// perturbation was used. If no feasible basis is found,
+// its status indicates why; otherwise, it is Working.
+func SimplexPhase1WithOptions(lp *StandardLP, pr PivotRule, dense bool,
+	opts *Options) (*SimplexTableau, *Result) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	pr = newOptionsPivotRule(pr, opts)
+	var spent int
+	if opts.Perturbation != 0 {
+		tableau, res, ok := perturbedPhase1(lp, pr, dense, opts.Perturbation)
+		if ok {
+			return tableau, res
+		}
+		spent = res.Phase1Iterations
+		dropPendingProgress(pr)
+	}
+	res := &Result{Phase1Iterations: spent}
+	tableau := simplexPhase1(lp, pr, dense, res)
+	return tableau, res
+}
+
+// perturbedPhase1 runs phase 1 on a perturbed version of
+// the program and then restores the original right-hand
+// side, as described by SimplexPhase1WithOptions.
+//
+// If this does not produce a phase 2 tableau for lp, the
+// tableau is nil and ok is false, unless the algorithm
+// was stopped early. Either way, the result counts the
+// pivots that were made.
+func perturbedPhase1(lp *StandardLP, pr PivotRule, dense bool,
+	scale float64) (tableau *SimplexTableau, res *Result, ok bool) {
+	perturbed := perturbProgram(lp, scale)
+	tableau = NewTableauPhase1(perturbed, dense)
+	res = &Result{Perturbed: true}
+	if status := runPhase1(tableau, pr, &res.Phase1Iterations); status != Optimal {
+		res.Status = status
+		return nil, res, interrupted(status)
+	}
+
+	// The artificial columns hold the inverse of the basis,
+	// so they translate the change in the right-hand side
+	// into a change of the basic values.
+	tableau.lp = lp
+	delta := append(Vector{}, lp.ConstraintVector...)
+	delta.Add(perturbed.ConstraintVector, -1)
+	for i := range delta {
+		if perturbed.ConstraintVector[i] < 0 {
+			delta[i] *= -1
+		}
+	}
+	valueCol := tableau.Matrix.Cols() - 1
+	for row := 0; row < tableau.Matrix.Rows(); row++ {
+		shift := 0.0
+		for i, d := range delta {
+			entry := tableau.Matrix.At(row, lp.Dim()+i)
+			if row == tableau.Matrix.Rows()-1 {
+				// The phase 1 cost of an artificial is -1.
+				entry++
+			}
+			shift += entry * d
+		}
+		tableau.Matrix.Set(row, valueCol, tableau.Matrix.At(row, valueCol)+shift)
+	}
+
+	// An optimal phase 1 tableau is dual feasible.
+	status, _ := runDualSimplex(tableau, newOptionsDualPivotRule(GreedyDualPivotRule{}, pr),
+		&res.Phase1Iterations)
+	if status != Optimal {
+		res.Status = status
+		return nil, res, interrupted(status)
+	}
+	eps := tableau.Matrix.AbsMax() * relativeEpsilon
+	if tableau.ObjectiveValue() < -eps || !tableau.phase1ToPhase2(lp) {
+		return nil, res, false
+	}
+	res.Status = Working
+	return tableau, res, true
+}
+
+// perturbedSimplex solves a perturbed version of the
+// program and then restores the original right-hand side.
+//
+// If the perturbed program is not solved to optimality,
+// or if the original program turns out to be infeasible,
+// ok is false, unless the algorithm was stopped early.
+// In that case, the result has no solution, since points
+// of the perturbed program are not feasible for the
+// original one. Either way, the result counts the pivots
+// that were made.
+func perturbedSimplex(lp *StandardLP, pr PivotRule, dense bool,
+	scale float64) (res *Result, ok bool) {
+	res = &Result{Perturbed: true}
+	tableau := simplexPhase1(perturbProgram(lp, scale), pr, dense, res)
+	if tableau == nil {
+		return res, interrupted(res.Status)
+	}
+	status, _ := runSimplex(tableau, pr, &res.Phase2Iterations)
+	if status == Optimal && tableau.SetConstraintVector(lp.ConstraintVector) {
+		status, _ = runDualSimplex(tableau, newOptionsDualPivotRule(GreedyDualPivotRule{}, pr),
+			&res.Phase2Iterations)
+		if status == Optimal {
+			tableau.fillResult(res, Optimal, -1)
+			return res, true
+		}
+	}
+	res.Status = status
+	return res, interrupted(status)
+}
+
+// perturbProgram creates a copy of lp with a randomly
+// perturbed right-hand side.
+func perturbProgram(lp *StandardLP, scale float64) *StandardLP {
+	// A fixed seed keeps the results reproducible.
+	gen := rand.New(rand.NewSource(1))
+	perturbed := *lp
+	perturbed.ConstraintVector = make(Vector, len(lp.ConstraintVector))
+	for i, b := range lp.ConstraintVector {
+		// Moving b away from zero makes the artificial
+		// variables of phase 1 strictly positive, and it
+		// keeps the sign of every row of the phase 1
+		// tableau.
+		delta := scale * (1 + math.Abs(b)) * (0.5 + 0.5*gen.Float64())
+		if b < 0 {
+			delta = -delta
+		}
+		perturbed.ConstraintVector[i] = b + delta
+	}
+	return &perturbed
+}
+
+// optionsPivotRule wraps a PivotRule to stop the simplex
+// method once a limit from Options is reached, and to
+// report progress.
+type optionsPivotRule struct {
+	PivotRule
+
+	opts       *Options
+	iterations int
+
+	// pending is the pivot which has been observed but not
+	// yet reported, since it had not been applied yet.
+	pending *Progress
+}
+
+// newOptionsPivotRule wraps pr in an optionsPivotRule if
+// opts sets any limits or a progress callback.
+func newOptionsPivotRule(pr PivotRule, opts *Options) PivotRule {
+	if opts.Context != nil || opts.MaxIterations > 0 || !opts.Deadline.IsZero() ||
+		opts.Progress != nil {
+		return &optionsPivotRule{PivotRule: pr, opts: opts}
+	}
+	return pr
+}
+
+func (o *optionsPivotRule) ChoosePivot(s *SimplexTableau) (int, int, SimplexStatus) {
+	o.report(s)
+	if status := o.limitStatus(); status != Working {
+		return -1, -1, status
+	}
+	return o.PivotRule.ChoosePivot(s)
+}
+
+// limitStatus returns the status for the first limit from
+// Options which has been reached, or Working if there is
+// none.
+func (o *optionsPivotRule) limitStatus() SimplexStatus {
+	if o.opts.Context != nil && o.opts.Context.Err() != nil {
+		return Cancelled
+	}
+	if o.opts.MaxIterations > 0 && o.iterations >= o.opts.MaxIterations {
+		return IterationLimit
+	}
+	if !o.opts.Deadline.IsZero() && time.Now().After(o.opts.Deadline) {
+		return IterationLimit
+	}
+	return Working
+}
+
+// ObservePivot counts pivots, and forwards them to the
+// wrapped rule if it is a PivotObserver.
+//
+// The pivot is reported on the next call to ChoosePivot,
+// once it has been applied to the tableau.
+func (o *optionsPivotRule) ObservePivot(s *SimplexTableau, leaving, entering int) {
+	o.count(s, leaving, entering)
+	if observer, ok := o.PivotRule.(PivotObserver); ok {
+		observer.ObservePivot(s, leaving, entering)
+	}
+}
+
+// count counts a pivot on s and makes it pending if
+// progress is being reported.
+func (o *optionsPivotRule) count(s *SimplexTableau, leaving, entering int) {
+	o.iterations++
+	if o.opts.Progress != nil {
+		phase := 2
+		if s.Dim() > s.lp.Dim() {
+			// Only phase 1 tableaus have artificial columns.
+			phase = 1
+		}
+		o.pending = &Progress{
+			Iteration: o.iterations,
+			Phase:     phase,
+			Entering:  entering,
+			Leaving:   leaving,
+		}
+	}
+}
+
+// report fills in the pending pivot, if there is one,
+// from the tableau and passes it to the progress
+// callback.
+func (o *optionsPivotRule) report(s *SimplexTableau) {
+	p := o.pending
+	if p == nil {
+		return
+	}
+	o.pending = nil
+	solution := s.Solution()
+	p.Objective = s.lp.Objective.Dot(solution[:s.lp.Dim()])
+	p.PrimalInfeasibility = s.primalInfeasibility()
+	o.opts.Progress(*p)
+}
+
+// optionsDualPivotRule wraps a DualPivotRule to stop the
+// dual simplex method once a limit from Options is
+// reached, sharing the iteration count and progress
+// reports of an optionsPivotRule.
+type optionsDualPivotRule struct {
+	DualPivotRule
+
+	primal *optionsPivotRule
+}
+
+// newOptionsDualPivotRule wraps pr in an
+// optionsDualPivotRule if primal is an optionsPivotRule.
+func newOptionsDualPivotRule(pr DualPivotRule, primal PivotRule) DualPivotRule {
+	if o, ok := primal.(*optionsPivotRule); ok {
+		return &optionsDualPivotRule{DualPivotRule: pr, primal: o}
+	}
+	return pr
+}
+
+func (o *optionsDualPivotRule) ChooseDualPivot(s *SimplexTableau) (int, int, SimplexStatus) {
+	o.primal.report(s)
+	if status := o.primal.limitStatus(); status != Working {
+		return -1, -1, status
+	}
+	leaving, entering, status := o.DualPivotRule.ChooseDualPivot(s)
+	if status == Working {
+		// The pivot is made right after this call.
+		o.primal.count(s, leaving, entering)
+	}
+	return leaving, entering, status
+}
+
+// dropPendingProgress discards a pivot which was counted
+// but not reported, since it was abandoned along with the
+// tableau of a perturbed program.
+func dropPendingProgress(pr PivotRule) {
+	if o, ok := pr.(*optionsPivotRule); ok {
+		o.pending = nil
+	}
+}
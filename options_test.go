@@ -0,0 +1,101 @@
+package linprog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSimplexLimits(t *testing.T) {
+	problem := randomStandardLP(20, 40)
+	full := Simplex(problem, BlandPivotRule{}, true)
+	if full.Phase2Iterations < 2 {
+		t.Skip("problem is too easy")
+	}
+
+	limit := full.Phase1Iterations + 1
+	res := SimplexWithOptions(problem, BlandPivotRule{}, true, &Options{MaxIterations: limit})
+	if res.Status != IterationLimit {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	if res.Phase1Iterations+res.Phase2Iterations != limit {
+		t.Errorf("expected %d iterations but got %d", limit,
+			res.Phase1Iterations+res.Phase2Iterations)
+	}
+	testBoundedSolution(t, problem, res.Solution)
+
+	res = SimplexWithOptions(problem, &DevexPivotRule{}, true, &Options{MaxIterations: 1})
+	if res.Status != IterationLimit || res.Solution != nil {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Solution)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res = SimplexWithOptions(problem, GreedyPivotRule{}, true, &Options{Context: ctx})
+	if res.Status != Cancelled || res.Phase1Iterations != 0 {
+		t.Errorf("unexpected result: %v %d", res.Status, res.Phase1Iterations)
+	}
+
+	res = SimplexWithOptions(problem, GreedyPivotRule{}, true,
+		&Options{Deadline: time.Now().Add(-time.Second), Perturbation: 1e-6})
+	if res.Status != IterationLimit {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+
+	res = SimplexWithOptions(problem, GreedyPivotRule{}, true,
+		&Options{Context: context.Background(), Deadline: time.Now().Add(time.Hour)})
+	if res.Status != full.Status {
+		t.Errorf("expected status %v but got %v", full.Status, res.Status)
+	}
+}
+
+func TestSimplexLimitsPerturbation(t *testing.T) {
+	// A large perturbation often needs dual simplex pivots
+	// to restore the right-hand side. They count towards
+	// the limit too, so any limit up to the total number
+	// of pivots stops the algorithm.
+	for i := 0; i < 10; i++ {
+		problem := randomStandardLP(10, 20)
+		opts := &Options{Perturbation: 0.5}
+		full := SimplexWithOptions(problem, GreedyPivotRule{}, true, opts)
+		total := full.Phase1Iterations + full.Phase2Iterations
+		if full.Status != Optimal || total == 0 {
+			continue
+		}
+		for limit := 1; limit <= total; limit++ {
+			opts.MaxIterations = limit
+			res := SimplexWithOptions(problem, GreedyPivotRule{}, true, opts)
+			if res.Status != IterationLimit {
+				t.Fatalf("limit %d: unexpected status: %v", limit, res.Status)
+			}
+			if n := res.Phase1Iterations + res.Phase2Iterations; n > limit {
+				t.Fatalf("limit %d: got %d iterations", limit, n)
+			}
+		}
+	}
+
+	// When the perturbed program is infeasible, the pivots
+	// spent on it leave too few for the original program.
+	problem := randomStandardLP(10, 20)
+	ones := make(Vector, problem.Dim())
+	for i := range ones {
+		ones[i] = 1
+	}
+	matrix := problem.ConstraintMatrix.(*DenseMatrix)
+	matrix.Data = append(matrix.Data, ones...)
+	matrix.NumRows++
+	problem.ConstraintVector = append(problem.ConstraintVector, -1)
+	plain := Simplex(problem, GreedyPivotRule{}, true)
+	if plain.Status != Infeasible {
+		t.Fatalf("unexpected status: %v", plain.Status)
+	}
+	limit := plain.Phase1Iterations
+	opts := &Options{Perturbation: 1e-6, MaxIterations: limit}
+	res := SimplexWithOptions(problem, GreedyPivotRule{}, true, opts)
+	if res.Status != IterationLimit {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+	if n := res.Phase1Iterations + res.Phase2Iterations; n > limit {
+		t.Errorf("expected at most %d iterations but got %d", limit, n)
+	}
+}
@@ -1,6 +1,9 @@
 package linprog
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // SimplexStatus is the status of an instance of the
 // simplex algorithm.
@@ -17,19 +20,81 @@ const (
 	// Unbounded indicates that the objective is unbounded
 	// and an infinitely large value can be achieved.
 	Unbounded
+
+	// Infeasible indicates that the constraints cannot be
+	// satisfied.
+	Infeasible
+
+	// IterationLimit indicates that the algorithm stopped
+	// before finishing because it ran out of iterations or
+	// time.
+	IterationLimit
+
+	// NumericalFailure indicates that the algorithm could
+	// not continue due to numerical problems, such as a
+	// zero or non-finite pivot element.
+	NumericalFailure
+
+	// Cancelled indicates that the algorithm stopped
+	// before finishing because its context was done.
+	Cancelled
 )
 
+// String returns a human-readable name for the status.
+func (s SimplexStatus) String() string {
+	switch s {
+	case Working:
+		return "Working"
+	case Optimal:
+		return "Optimal"
+	case Unbounded:
+		return "Unbounded"
+	case Infeasible:
+		return "Infeasible"
+	case IterationLimit:
+		return "IterationLimit"
+	case NumericalFailure:
+		return "NumericalFailure"
+	case Cancelled:
+		return "Cancelled"
+	default:
+		return fmt.Sprintf("SimplexStatus(%d)", int(s))
+	}
+}
+
 // A PivotRule is a rule for determining which pivot to
 // make in each iteration of the simplex method.
 //
 // It also indicates if the algorithm should halt.
+// If the status is Unbounded, the entering variable is
+// one which can be increased without bound, and the
+// leaving variable is -1.
 type PivotRule interface {
 	ChoosePivot(s *SimplexTableau) (leaving, entering int, status SimplexStatus)
 }
 
+// A PivotObserver is a PivotRule which is notified of each
+// pivot performed by the simplex method, so that it can
+// keep state up to date across pivots.
+//
+// ObservePivot is called just before the pivot is applied
+// to the tableau, with the variables returned by
+// ChoosePivot.
+type PivotObserver interface {
+	PivotRule
+	ObservePivot(s *SimplexTableau, leaving, entering int)
+}
+
 // BlandPivotRule is a PivotRule that implements Bland's
 // rule for avoiding cycles in the simplex method.
-type BlandPivotRule struct{}
+type BlandPivotRule struct {
+	// RatioTest chooses the leaving variable.
+	// If it is nil, StandardRatioTest is used.
+	//
+	// Ratio tests other than StandardRatioTest may break
+	// the guarantee that Bland's rule does not cycle.
+	RatioTest RatioTest
+}
 
 func (b BlandPivotRule) ChoosePivot(s *SimplexTableau) (int, int, SimplexStatus) {
 	enterVar := -1
@@ -39,19 +104,16 @@ func (b BlandPivotRule) ChoosePivot(s *SimplexTableau) (int, int, SimplexStatus)
 			break
 		}
 	}
-	if enterVar == -1 {
-		return 0, 0, Optimal
-	}
-	leaveVar := minRatioLeaveVariable(s, enterVar)
-	if leaveVar == -1 {
-		return 0, 0, Unbounded
-	}
-	return leaveVar, enterVar, Working
+	return finishPivot(s, enterVar, b.RatioTest)
 }
 
 // GreedyPivotRule is a PivotRule that picks the column
 // with the highest relative cost coefficient.
-type GreedyPivotRule struct{}
+type GreedyPivotRule struct {
+	// RatioTest chooses the leaving variable.
+	// If it is nil, StandardRatioTest is used.
+	RatioTest RatioTest
+}
 
 func (g GreedyPivotRule) ChoosePivot(s *SimplexTableau) (int, int, SimplexStatus) {
 	enterVar := -1
@@ -62,29 +124,74 @@ func (g GreedyPivotRule) ChoosePivot(s *SimplexTableau) (int, int, SimplexStatus
 			bestCost = cost
 		}
 	}
+	return finishPivot(s, enterVar, g.RatioTest)
+}
+
+// ParsePivotRule creates a pivot rule from its name, which
+// is "bland", "greedy", "steepest", or "devex", using the
+// given ratio test to choose leaving variables.
+func ParsePivotRule(name string, rt RatioTest) (PivotRule, error) {
+	switch name {
+	case "bland":
+		return BlandPivotRule{RatioTest: rt}, nil
+	case "greedy":
+		return GreedyPivotRule{RatioTest: rt}, nil
+	case "steepest":
+		return &SteepestEdgePivotRule{RatioTest: rt}, nil
+	case "devex":
+		return &DevexPivotRule{RatioTest: rt}, nil
+	default:
+		return nil, fmt.Errorf("unknown pivot rule: %s", name)
+	}
+}
+
+// finishPivot uses a ratio test to find the leaving
+// variable for a pivot rule's entering variable, which is
+// -1 if the tableau is optimal.
+func finishPivot(s *SimplexTableau, enterVar int, rt RatioTest) (int, int, SimplexStatus) {
 	if enterVar == -1 {
 		return 0, 0, Optimal
 	}
-	leaveVar := minRatioLeaveVariable(s, enterVar)
+	if rt == nil {
+		rt = StandardRatioTest{}
+	}
+	leaveVar := rt.ChooseLeaving(s, enterVar)
 	if leaveVar == -1 {
-		return 0, 0, Unbounded
+		return -1, enterVar, Unbounded
 	}
 	return leaveVar, enterVar, Working
 }
 
+// minRatioLeaveVariable finds the variable which limits
+// how far the entering variable can increase.
+//
+// This is usually a basic variable that reaches zero, but
+// for programs with upper bounds, it may be a basic
+// variable that reaches its upper bound, or the entering
+// variable itself if it reaches its upper bound first.
+// If nothing limits the entering variable, -1 is returned.
 func minRatioLeaveVariable(s *SimplexTableau, enterVar int) int {
 	leaveVar := -1
 	minRatio := math.Inf(1)
+	if bound := s.upperBound(enterVar); !math.IsInf(bound, 1) {
+		leaveVar = enterVar
+		minRatio = bound
+	}
 	entries := s.Matrix.CopyCol(enterVar)
 	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
 	for row, basic := range s.RowToBasic {
 		entry := entries[row]
+		var ratio float64
 		if entry > 0 {
-			ratio := values[row] / entry
-			if ratio < minRatio {
-				minRatio = ratio
-				leaveVar = basic
-			}
+			ratio = values[row] / entry
+		} else if bound := s.upperBound(basic); entry < 0 && !math.IsInf(bound, 1) {
+			ratio = (bound - values[row]) / -entry
+		} else {
+			continue
+		}
+		if ratio < minRatio {
+			minRatio = ratio
+			leaveVar = basic
 		}
 	}
 	return leaveVar
@@ -0,0 +1,77 @@
+package linprog
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Progress describes the state of the simplex method
+// right after a pivot.
+type Progress struct {
+	// Iteration is the number of pivots so far, counting
+	// both phases.
+	Iteration int
+
+	// Phase is 1 while searching for a feasible point, and
+	// 2 while optimizing the objective.
+	Phase int
+
+	// Objective is the value of the true objective at the
+	// current point.
+	Objective float64
+
+	// PrimalInfeasibility is the total amount by which the
+	// current point violates the constraints and bounds.
+	// It is zero in phase 2, except during dual simplex
+	// pivots, which restore feasibility after a perturbed
+	// right-hand side is reverted.
+	PrimalInfeasibility float64
+
+	// Entering and Leaving are the variables of the pivot,
+	// which are equal for a bound flip.
+	// In phase 1, indices past the dimension of the program
+	// refer to artificial variables.
+	Entering int
+	Leaving  int
+}
+
+// NewProgressLogger creates a function for
+// Options.Progress which prints a table of progress to w.
+//
+// A row is printed every frequency iterations.
+// If frequency is less than 1, every iteration is printed.
+func NewProgressLogger(w io.Writer, frequency int) func(p Progress) {
+	if frequency < 1 {
+		frequency = 1
+	}
+	printedHeader := false
+	return func(p Progress) {
+		if p.Iteration%frequency != 0 {
+			return
+		}
+		if !printedHeader {
+			fmt.Fprintf(w, "%10s %5s %16s %12s %8s %8s\n", "Iteration", "Phase",
+				"Objective", "Primal Inf.", "Enter", "Leave")
+			printedHeader = true
+		}
+		fmt.Fprintf(w, "%10d %5d %16.8e %12.4e %8d %8d\n", p.Iteration, p.Phase,
+			p.Objective, p.PrimalInfeasibility, p.Entering, p.Leaving)
+	}
+}
+
+// primalInfeasibility sums the values of the artificial
+// variables and the bound violations of the other basic
+// variables.
+func (s *SimplexTableau) primalInfeasibility() float64 {
+	var res float64
+	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	for basic, row := range s.BasicToRow {
+		if basic >= s.lp.Dim() {
+			res += math.Abs(values[row])
+		} else {
+			res += s.boundViolation(basic, values[row])
+		}
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package linprog
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSimplexProgress(t *testing.T) {
+	var reports []Progress
+	var res *Result
+	for i := 0; i < 10; i++ {
+		reports = nil
+		res = SimplexWithOptions(randomStandardLP(20, 40), GreedyPivotRule{}, true, &Options{
+			Progress: func(p Progress) {
+				reports = append(reports, p)
+			},
+		})
+		if res.Status == Optimal {
+			break
+		}
+	}
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	total := res.Phase1Iterations + res.Phase2Iterations
+	if len(reports) != total {
+		t.Fatalf("expected %d reports but got %d", total, len(reports))
+	}
+	for i, p := range reports {
+		if p.Iteration != i+1 {
+			t.Errorf("report %d: unexpected iteration %d", i, p.Iteration)
+		}
+		expectedPhase := 1
+		if i >= res.Phase1Iterations {
+			expectedPhase = 2
+		}
+		if p.Phase != expectedPhase {
+			t.Errorf("report %d: expected phase %d but got %d", i, expectedPhase, p.Phase)
+		}
+		if p.Phase == 2 && p.PrimalInfeasibility != 0 {
+			t.Errorf("report %d: unexpected infeasibility %f", i, p.PrimalInfeasibility)
+		}
+	}
+	if res.Phase1Iterations > 0 {
+		if inf := reports[res.Phase1Iterations-1].PrimalInfeasibility; inf > 1e-8 {
+			t.Errorf("phase 1 ended with infeasibility %f", inf)
+		}
+	}
+	if last := reports[len(reports)-1]; math.Abs(last.Objective-res.Objective) > 1e-8 {
+		t.Errorf("expected final objective %f but got %f", res.Objective, last.Objective)
+	}
+
+	// Dual simplex pivots after a perturbation are
+	// reported as well.
+	for i := 0; i < 10; i++ {
+		reports = nil
+		res = SimplexWithOptions(randomStandardLP(10, 20), GreedyPivotRule{}, true, &Options{
+			Perturbation: 0.5,
+			Progress: func(p Progress) {
+				reports = append(reports, p)
+			},
+		})
+		if total := res.Phase1Iterations + res.Phase2Iterations; len(reports) != total {
+			t.Fatalf("expected %d reports but got %d", total, len(reports))
+		}
+		for j, p := range reports {
+			if p.Iteration != j+1 {
+				t.Fatalf("report %d: unexpected iteration %d", j, p.Iteration)
+			}
+		}
+	}
+}
+
+func TestProgressLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewProgressLogger(&buf, 3)
+	for i := 1; i <= 10; i++ {
+		logger(Progress{Iteration: i, Phase: 1, Objective: float64(i)})
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines but got %d:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "Iteration") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if fields := strings.Fields(lines[3]); fields[0] != "9" {
+		t.Errorf("unexpected last row: %s", lines[3])
+	}
+}
@@ -0,0 +1,159 @@
+package linprog
+
+import "math/big"
+
+// A RatVector is a vector of exact rational numbers.
+type RatVector []*big.Rat
+
+// NewRatVector converts a Vector into a RatVector.
+// The conversion is exact, since every finite float64 is
+// a rational number.
+func NewRatVector(v Vector) RatVector {
+	res := make(RatVector, len(v))
+	for i, x := range v {
+		res[i] = new(big.Rat).SetFloat64(x)
+	}
+	return res
+}
+
+// NewRatVectorZero creates a vector of zeros.
+func NewRatVectorZero(size int) RatVector {
+	res := make(RatVector, size)
+	for i := range res {
+		res[i] = new(big.Rat)
+	}
+	return res
+}
+
+// Float converts the vector to the nearest Vector.
+func (r RatVector) Float() Vector {
+	res := make(Vector, len(r))
+	for i, x := range r {
+		res[i], _ = x.Float64()
+	}
+	return res
+}
+
+// Dot computes the dot product of two vectors.
+func (r RatVector) Dot(r1 RatVector) *big.Rat {
+	if len(r) != len(r1) {
+		panic("dimension mismatch")
+	}
+	res := new(big.Rat)
+	var product big.Rat
+	for i, x := range r {
+		res.Add(res, product.Mul(x, r1[i]))
+	}
+	return res
+}
+
+// A RatMatrix is a dense matrix of exact rational
+// numbers.
+//
+// It mirrors the row operations of Matrix, which are all
+// that the simplex method needs.
+type RatMatrix struct {
+	NumRows int
+	NumCols int
+	Data    []*big.Rat
+}
+
+// NewRatMatrix creates a matrix of zeros.
+func NewRatMatrix(rows, cols int) *RatMatrix {
+	return &RatMatrix{
+		NumRows: rows,
+		NumCols: cols,
+		Data:    NewRatVectorZero(rows * cols),
+	}
+}
+
+// NewRatMatrixFloat converts a Matrix into a RatMatrix.
+// As with NewRatVector, the conversion is exact.
+func NewRatMatrixFloat(m Matrix) *RatMatrix {
+	res := NewRatMatrix(m.Rows(), m.Cols())
+	for i := 0; i < m.Rows(); i++ {
+		for j, x := range m.CopyRow(i) {
+			if x != 0 {
+				res.Data[i*res.NumCols+j].SetFloat64(x)
+			}
+		}
+	}
+	return res
+}
+
+func (r *RatMatrix) Rows() int {
+	return r.NumRows
+}
+
+func (r *RatMatrix) Cols() int {
+	return r.NumCols
+}
+
+// At gets an entry of the matrix.
+// The result should not be modified.
+func (r *RatMatrix) At(i, j int) *big.Rat {
+	if i < 0 || j < 0 || i >= r.NumRows || j >= r.NumCols {
+		panic("index out of range")
+	}
+	return r.Data[i*r.NumCols+j]
+}
+
+// Set copies a value into an entry of the matrix.
+func (r *RatMatrix) Set(i, j int, value *big.Rat) {
+	if i < 0 || j < 0 || i >= r.NumRows || j >= r.NumCols {
+		panic("index out of range")
+	}
+	r.Data[i*r.NumCols+j].Set(value)
+}
+
+func (r *RatMatrix) ScaleRow(i int, s *big.Rat) {
+	for _, x := range r.Row(i) {
+		x.Mul(x, s)
+	}
+}
+
+func (r *RatMatrix) AddRow(source, dest int, sourceScale *big.Rat) {
+	if sourceScale.Sign() == 0 {
+		return
+	}
+	var product big.Rat
+	destRow := r.Row(dest)
+	for i, x := range r.Row(source) {
+		if x.Sign() != 0 {
+			destRow[i].Add(destRow[i], product.Mul(x, sourceScale))
+		}
+	}
+}
+
+// Row gets a row of the matrix, sharing its entries with
+// the matrix.
+func (r *RatMatrix) Row(i int) RatVector {
+	if i < 0 || i >= r.NumRows {
+		panic("index out of range")
+	}
+	return r.Data[i*r.NumCols : (i+1)*r.NumCols]
+}
+
+func (r *RatMatrix) Copy() *RatMatrix {
+	res := NewRatMatrix(r.NumRows, r.NumCols)
+	for i, x := range r.Data {
+		res.Data[i].Set(x)
+	}
+	return res
+}
+
+func (r *RatMatrix) CopyRow(i int) RatVector {
+	res := NewRatVectorZero(r.NumCols)
+	for j, x := range r.Row(i) {
+		res[j].Set(x)
+	}
+	return res
+}
+
+func (r *RatMatrix) CopyCol(i int) RatVector {
+	res := NewRatVectorZero(r.NumRows)
+	for j := range res {
+		res[j].Set(r.At(j, i))
+	}
+	return res
+}
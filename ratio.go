@@ -0,0 +1,191 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+)
+
+// A RatioTest chooses the leaving variable for a pivot,
+// given the entering variable.
+//
+// The result follows the conventions of Pivot: it is the
+// entering variable itself for a bound flip, or -1 if
+// nothing limits the entering variable.
+//
+// Every PivotRule in this package takes a RatioTest. The
+// revised simplex method works without a tableau, so its
+// PricingRules do not.
+type RatioTest interface {
+	ChooseLeaving(s *SimplexTableau, entering int) int
+}
+
+// StandardRatioTest is the textbook ratio test, which
+// picks the variable that limits the entering variable
+// the most, no matter how small its pivot entry is.
+type StandardRatioTest struct{}
+
+func (s StandardRatioTest) ChooseLeaving(t *SimplexTableau, entering int) int {
+	return minRatioLeaveVariable(t, entering)
+}
+
+// ParseRatioTest creates a ratio test with default
+// settings from its name, which is "standard", "harris",
+// or "lex".
+func ParseRatioTest(name string) (RatioTest, error) {
+	switch name {
+	case "standard":
+		return StandardRatioTest{}, nil
+	case "harris":
+		return HarrisRatioTest{}, nil
+	case "lex":
+		return LexicographicRatioTest{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ratio test: %s", name)
+	}
+}
+
+// HarrisRatioTest is a two-pass ratio test which trades a
+// small amount of infeasibility for numerical stability.
+//
+// The first pass computes the largest step for which no
+// basic variable leaves its bounds by more than the
+// feasibility tolerance. The second pass picks, among the
+// variables which reach their bounds within that step,
+// the one with the largest pivot entry.
+// Pivot entries which are tiny relative to the entering
+// column are ignored altogether.
+type HarrisRatioTest struct {
+	// Tolerance is the feasibility tolerance, relative to
+	// the magnitude of the basic values.
+	//
+	// The default is 1e-9.
+	Tolerance float64
+}
+
+func (h HarrisRatioTest) ChooseLeaving(s *SimplexTableau, entering int) int {
+	entries := s.Matrix.CopyCol(entering)
+	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	numRows := s.Matrix.Rows() - 1
+	pivotEpsilon := relativeEpsilon * entries[:numRows].AbsMax()
+	tolerance := h.Tolerance
+	if tolerance == 0 {
+		tolerance = 1e-9
+	}
+	tolerance *= math.Max(1, values[:numRows].AbsMax())
+
+	// distance computes how far the entering variable can
+	// move before the basic variable in a row reaches its
+	// bound, loosened by a slack, or returns false if the
+	// row does not limit the entering variable.
+	distance := func(row, basic int, slack float64) (float64, bool) {
+		entry := entries[row]
+		if entry > pivotEpsilon {
+			return (values[row] + slack) / entry, true
+		} else if bound := s.upperBound(basic); entry < -pivotEpsilon &&
+			!math.IsInf(bound, 1) {
+			return (bound - values[row] + slack) / -entry, true
+		}
+		return 0, false
+	}
+
+	maxStep := s.upperBound(entering)
+	for row, basic := range s.RowToBasic {
+		if d, ok := distance(row, basic, tolerance); ok {
+			maxStep = math.Min(maxStep, d)
+		}
+	}
+	if math.IsInf(maxStep, 1) {
+		return -1
+	}
+	if s.upperBound(entering) <= maxStep {
+		return entering
+	}
+
+	leaveVar := -1
+	bestEntry := 0.0
+	for row, basic := range s.RowToBasic {
+		if d, ok := distance(row, basic, 0); ok && d <= maxStep {
+			if entry := math.Abs(entries[row]); entry > bestEntry {
+				leaveVar = basic
+				bestEntry = entry
+			}
+		}
+	}
+	return leaveVar
+}
+
+// LexicographicRatioTest is a ratio test which breaks ties
+// between rows lexicographically, which prevents the
+// simplex method from cycling with any entering rule,
+// such as GreedyPivotRule.
+//
+// Rows are compared by their ratios, and then by their
+// entries in the columns of the basis at the start of the
+// current phase, divided by their pivot entries. This is
+// equivalent to perturbing the right-hand side by
+// infinitesimals of decreasing order.
+// The guarantee against cycling does not extend to
+// programs with upper bounds.
+//
+// Tableaus which were not created by NewTableauPhase1 or
+// NewTableauBasis fall back to StandardRatioTest.
+type LexicographicRatioTest struct{}
+
+func (l LexicographicRatioTest) ChooseLeaving(s *SimplexTableau, entering int) int {
+	if s.lexBasis == nil {
+		return minRatioLeaveVariable(s, entering)
+	}
+	entries := s.Matrix.CopyCol(entering)
+	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	ratios := map[int]float64{}
+	minRatio := math.Inf(1)
+	for row, basic := range s.RowToBasic {
+		entry := entries[row]
+		if entry > 0 {
+			ratios[row] = values[row] / entry
+		} else if bound := s.upperBound(basic); entry < 0 && !math.IsInf(bound, 1) {
+			ratios[row] = (bound - values[row]) / -entry
+		} else {
+			continue
+		}
+		minRatio = math.Min(minRatio, ratios[row])
+	}
+	bound := s.upperBound(entering)
+	if len(ratios) == 0 {
+		if math.IsInf(bound, 1) {
+			return -1
+		}
+		return entering
+	}
+	epsilon := relativeEpsilon * math.Max(1, math.Abs(minRatio))
+	if bound <= minRatio+epsilon {
+		return entering
+	}
+
+	bestRow := -1
+	for row, ratio := range ratios {
+		if ratio > minRatio+epsilon {
+			continue
+		}
+		if bestRow == -1 || s.lexLess(row, bestRow, entries) {
+			bestRow = row
+		}
+	}
+	return s.RowToBasic[bestRow]
+}
+
+// lexLess checks if a row comes before another row in the
+// lexicographic order, given the entering column.
+func (s *SimplexTableau) lexLess(row1, row2 int, entries Vector) bool {
+	for _, col := range s.lexBasis {
+		x1 := s.Matrix.At(row1, col) / entries[row1]
+		x2 := s.Matrix.At(row2, col) / entries[row2]
+		epsilon := relativeEpsilon * math.Max(1, math.Max(math.Abs(x1), math.Abs(x2)))
+		if x1 < x2-epsilon {
+			return true
+		} else if x1 > x2+epsilon {
+			return false
+		}
+	}
+	return row1 < row2
+}
@@ -0,0 +1,85 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHarrisRatioTest(t *testing.T) {
+	problem := &StandardLP{
+		Objective: Vector{1, 0, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 2,
+			NumCols: 3,
+			Data: []float64{
+				1e-6, 1, 0,
+				1, 0, 1,
+			},
+		},
+		ConstraintVector: Vector{1e-6, 1 + 1e-12},
+	}
+	tableau := NewTableauBasis(problem, []int{1, 2})
+	if leaving := (StandardRatioTest{}).ChooseLeaving(tableau, 0); leaving != 1 {
+		t.Errorf("standard test should pick 1 but got %d", leaving)
+	}
+	if leaving := (HarrisRatioTest{}).ChooseLeaving(tableau, 0); leaving != 2 {
+		t.Errorf("Harris test should pick 2 but got %d", leaving)
+	}
+}
+
+func TestHarrisRatioTestSimplex(t *testing.T) {
+	rules := []PivotRule{
+		BlandPivotRule{RatioTest: HarrisRatioTest{}},
+		GreedyPivotRule{RatioTest: HarrisRatioTest{}},
+		&SteepestEdgePivotRule{RatioTest: HarrisRatioTest{}},
+		&DevexPivotRule{RatioTest: HarrisRatioTest{}},
+	}
+	for i := 0; i < 10; i++ {
+		problem := randomStandardLP(15, 30)
+		if i%2 == 1 {
+			problem.UpperBounds = NewVectorRandom(problem.Dim()).Abs()
+			problem.UpperBounds.Scale(3)
+		}
+		expected := Simplex(problem, GreedyPivotRule{}, true)
+		for _, rule := range rules {
+			actual := Simplex(problem, rule, true)
+			if actual.Status != expected.Status {
+				t.Fatalf("%T: expected status %v but got %v", rule, expected.Status,
+					actual.Status)
+			}
+			if expected.Status == Optimal &&
+				math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+				t.Errorf("%T: expected objective %f but got %f", rule, expected.Objective,
+					actual.Objective)
+			}
+		}
+	}
+}
+
+func TestParsePivotRule(t *testing.T) {
+	for _, ratio := range []string{"standard", "harris", "lex"} {
+		rt, err := ParseRatioTest(ratio)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"bland", "greedy", "steepest", "devex"} {
+			if _, err := ParsePivotRule(name, rt); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	for _, name := range []string{"bland", "greedy"} {
+		if _, err := ParsePricingRule(name); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := ParseRatioTest("random"); err == nil {
+		t.Error("expected an error for an unknown ratio test")
+	}
+	if _, err := ParsePivotRule("random", nil); err == nil {
+		t.Error("expected an error for an unknown pivot rule")
+	}
+	if _, err := ParsePricingRule("devex"); err == nil {
+		t.Error("expected an error for an unknown pricing rule")
+	}
+}
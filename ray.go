@@ -0,0 +1,48 @@
+package linprog
+
+import "math"
+
+// Ray computes the direction in which the current
+// solution moves as a non-basic variable is increased.
+//
+// If the variable's relative cost coefficient is positive
+// and no basic variable decreases along the direction,
+// the result is a certificate that the objective is
+// unbounded: a vector d with A*d = 0, d >= 0, and
+// c'*d > 0.
+func (s *SimplexTableau) Ray(entering int) Vector {
+	res := make(Vector, s.Dim())
+	res[entering] = 1
+	for basic, row := range s.BasicToRow {
+		res[basic] = -s.Matrix.At(row, entering)
+	}
+	for variable := range s.complemented {
+		res[variable] *= -1
+	}
+	return res
+}
+
+// VerifyRay checks if d is a certificate that lp is
+// unbounded, provided that lp is feasible.
+// In particular, it checks that A*d = 0, d >= 0, and
+// c'*d > 0, up to numerical tolerance.
+// Variables with finite upper bounds must have d_j = 0.
+func VerifyRay(lp *StandardLP, d Vector) bool {
+	if len(d) != lp.Dim() {
+		return false
+	}
+	epsilon := relativeEpsilon * d.AbsMax() * float64(len(d))
+	for i, x := range d {
+		if x < -epsilon || (x > epsilon && !math.IsInf(lp.UpperBound(i), 1)) {
+			return false
+		}
+	}
+	matrixEpsilon := epsilon * lp.ConstraintMatrix.AbsMax()
+	for i := range lp.ConstraintVector {
+		if dot := lp.ConstraintMatrix.CopyRow(i).Dot(d); dot > matrixEpsilon ||
+			dot < -matrixEpsilon {
+			return false
+		}
+	}
+	return lp.Objective.Dot(d) > epsilon*lp.Objective.AbsMax()
+}
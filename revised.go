@@ -0,0 +1,489 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+)
+
+// revisedRefactorInterval is the number of basis updates
+// after which the revised simplex method refactorizes the
+// basis from scratch.
+const revisedRefactorInterval = 50
+
+// A PricingRule chooses entering variables for the
+// revised simplex method.
+//
+// Unlike a PivotRule, it never sees a tableau, so it
+// cannot choose the leaving variable. The revised method
+// always uses the standard minimum ratio test for that.
+type PricingRule interface {
+	// ChooseEntering picks a variable with a positive
+	// relative cost coefficient, or returns -1 if there is
+	// none.
+	//
+	// Variables which may not enter the basis, including
+	// basic variables, have a coefficient of zero.
+	ChooseEntering(costs Vector) int
+}
+
+// BlandPricingRule is a PricingRule that picks the first
+// variable with a positive relative cost coefficient, like
+// BlandPivotRule.
+type BlandPricingRule struct{}
+
+func (b BlandPricingRule) ChooseEntering(costs Vector) int {
+	for i, cost := range costs {
+		if cost > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// GreedyPricingRule is a PricingRule that picks the
+// variable with the highest relative cost coefficient,
+// like GreedyPivotRule.
+type GreedyPricingRule struct{}
+
+func (g GreedyPricingRule) ChooseEntering(costs Vector) int {
+	enterVar := -1
+	bestCost := 0.0
+	for i, cost := range costs {
+		if cost > bestCost {
+			enterVar = i
+			bestCost = cost
+		}
+	}
+	return enterVar
+}
+
+// ParsePricingRule creates a pricing rule from its name,
+// which is "bland" or "greedy", like the pivot rules of
+// ParsePivotRule.
+func ParsePricingRule(name string) (PricingRule, error) {
+	switch name {
+	case "bland":
+		return BlandPricingRule{}, nil
+	case "greedy":
+		return GreedyPricingRule{}, nil
+	default:
+		return nil, fmt.Errorf("unknown pricing rule: %s", name)
+	}
+}
+
+// RevisedSimplex solves a linear program with the revised
+// simplex method.
+//
+// Rather than updating an entire tableau after every
+// pivot, the revised method keeps an LU factorization of
+// the basis matrix, updated in product form with one eta
+// vector per pivot and periodically refactorized.
+// Each iteration then only computes the dual vector, the
+// relative cost coefficients, and the entering column.
+//
+// The result is like that of Simplex, except that no
+// sensitivity analysis is performed.
+// Upper bounds are turned into rows with BoundsToRows, and
+// the result is mapped back to lp, except for the basis.
+func RevisedSimplex(lp *StandardLP, pr PricingRule) *Result {
+	res := revisedSimplex(lp.BoundsToRows(), pr)
+	lp.fromBoundRows(res)
+	return res
+}
+
+func revisedSimplex(lp *StandardLP, pr PricingRule) *Result {
+	s := newRevisedSolver(lp, pr)
+	res := &Result{}
+
+	// Phase 1 maximizes the negative sum of the
+	// artificial variables.
+	for i := range s.costs {
+		if i >= lp.Dim() {
+			s.costs[i] = -1
+		}
+	}
+	s.numEligible = len(s.costs)
+	status, _ := s.run(&res.Phase1Iterations)
+	if status == Unbounded {
+		// The phase 1 objective is bounded above by zero,
+		// so the entering variable's relative cost must be
+		// a rounding error, which refactorizing the basis
+		// removes.
+		if s.refactorize() {
+			status, _ = s.run(&res.Phase1Iterations)
+		} else {
+			status = NumericalFailure
+		}
+	}
+	switch status {
+	case Optimal:
+	case Unbounded:
+		res.Status = NumericalFailure
+		return res
+	default:
+		res.Status = status
+		return res
+	}
+	if s.objective() < -s.epsilon() {
+		res.Status = Infeasible
+		res.Farkas = s.farkas()
+		return res
+	}
+	if !s.removeArtificials() {
+		res.Status = NumericalFailure
+		return res
+	}
+
+	for i := range s.costs {
+		if i < lp.Dim() {
+			s.costs[i] = lp.Objective[i]
+		} else {
+			s.costs[i] = 0
+		}
+	}
+	s.numEligible = lp.Dim()
+	var entering int
+	res.Status, entering = s.run(&res.Phase2Iterations)
+	res.Basis = s.basisList()
+	switch res.Status {
+	case Optimal:
+		res.Solution = s.solution()
+		res.Objective = s.objective()
+		res.Duals = s.duals()
+		res.ReducedCosts = s.reducedCosts(res.Duals)
+		if !finiteVector(res.Solution) {
+			res.Status = NumericalFailure
+		}
+	case Unbounded:
+		res.Solution = s.solution()
+		res.Objective = s.objective()
+		res.Ray = s.ray(entering)
+	}
+	return res
+}
+
+type revisedEta struct {
+	position int
+	column   Vector
+}
+
+// revisedSolver stores the state of the revised simplex
+// method on the phase 1 system [S*A I]*x = S*b, where S
+// negates rows with negative b values.
+type revisedSolver struct {
+	lp *StandardLP
+	pr PricingRule
+
+	columns [][]sparseEntry
+	rhs     Vector
+	costs   Vector
+
+	// numEligible is the number of leading variables
+	// which may enter the basis.
+	numEligible int
+
+	basis   []int
+	isBasic []bool
+	values  Vector
+
+	lu   *LU
+	etas []revisedEta
+}
+
+func newRevisedSolver(lp *StandardLP, pr PricingRule) *revisedSolver {
+	numRows := len(lp.ConstraintVector)
+	numCols := lp.Dim() + numRows
+	s := &revisedSolver{
+		lp:      lp,
+		pr:      pr,
+		columns: make([][]sparseEntry, numCols),
+		rhs:     append(Vector{}, lp.ConstraintVector...),
+		costs:   make(Vector, numCols),
+		basis:   make([]int, numRows),
+		isBasic: make([]bool, numCols),
+	}
+	copy(s.columns, sparseColumns(lp.ConstraintMatrix))
+	for _, column := range s.columns[:lp.Dim()] {
+		for i, entry := range column {
+			if s.rhs[entry.row] < 0 {
+				column[i].value *= -1
+			}
+		}
+	}
+	for row := 0; row < numRows; row++ {
+		if s.rhs[row] < 0 {
+			s.rhs[row] *= -1
+		}
+		artificial := lp.Dim() + row
+		s.columns[artificial] = []sparseEntry{{row, 1}}
+		s.basis[row] = artificial
+		s.isBasic[artificial] = true
+	}
+	s.values = append(Vector{}, s.rhs...)
+	s.lu = NewLU(NewDenseMatrixIdentity(numRows))
+	return s
+}
+
+// run pivots until the pricing rule finds no entering
+// variable or the program is found to be unbounded.
+func (s *revisedSolver) run(iterations *int) (SimplexStatus, int) {
+	for {
+		costs := s.relativeCosts(s.dualVector())
+		entering := s.pr.ChooseEntering(costs)
+		if entering == -1 {
+			return Optimal, -1
+		}
+		column := s.ftran(s.denseColumn(entering))
+		position := s.ratioTest(column)
+		if position == -1 {
+			return Unbounded, entering
+		}
+		if !s.pivot(position, entering, column) {
+			return NumericalFailure, -1
+		}
+		*iterations++
+	}
+}
+
+// relativeCosts computes the relative cost coefficients
+// of the eligible non-basic variables, given the dual
+// vector.
+func (s *revisedSolver) relativeCosts(y Vector) Vector {
+	res := make(Vector, s.numEligible)
+	epsilon := s.epsilon()
+	for i := range res {
+		if s.isBasic[i] {
+			continue
+		}
+		cost := s.costs[i]
+		for _, entry := range s.columns[i] {
+			cost -= y[entry.row] * entry.value
+		}
+		if math.Abs(cost) > epsilon {
+			res[i] = cost
+		}
+	}
+	return res
+}
+
+// ratioTest finds the basis position which leaves first
+// as the entering variable increases, or -1 if none does.
+func (s *revisedSolver) ratioTest(column Vector) int {
+	epsilon := relativeEpsilon * column.AbsMax()
+	position := -1
+	minRatio := math.Inf(1)
+	for i, entry := range column {
+		if entry > epsilon {
+			ratio := math.Max(0, s.values[i]) / entry
+			if ratio < minRatio {
+				minRatio = ratio
+				position = i
+			}
+		}
+	}
+	return position
+}
+
+// pivot replaces the basic variable at a position with
+// the entering variable, given the entering variable's
+// column in terms of the current basis.
+func (s *revisedSolver) pivot(position, entering int, column Vector) bool {
+	step := math.Max(0, s.values[position]) / column[position]
+	s.values.Add(column, -step)
+	s.values[position] = step
+
+	s.isBasic[s.basis[position]] = false
+	s.isBasic[entering] = true
+	s.basis[position] = entering
+
+	s.etas = append(s.etas, revisedEta{position: position, column: column})
+	if len(s.etas) >= revisedRefactorInterval {
+		return s.refactorize()
+	}
+	return true
+}
+
+// refactorize computes a new LU factorization of the
+// basis and discards the eta file.
+func (s *revisedSolver) refactorize() bool {
+	numRows := len(s.basis)
+	matrix := NewDenseMatrix(numRows, numRows)
+	for position, variable := range s.basis {
+		for _, entry := range s.columns[variable] {
+			matrix.Set(entry.row, position, entry.value)
+		}
+	}
+	s.lu = NewLU(matrix)
+	s.etas = nil
+	if s.lu == nil {
+		return false
+	}
+	s.values = s.lu.Solve(s.rhs)
+	return true
+}
+
+// ftran solves B*x = v.
+func (s *revisedSolver) ftran(v Vector) Vector {
+	x := s.lu.Solve(v)
+	for _, eta := range s.etas {
+		pivot := x[eta.position] / eta.column[eta.position]
+		x.Add(eta.column, -pivot)
+		x[eta.position] = pivot
+	}
+	return x
+}
+
+// btran solves B'*y = v.
+func (s *revisedSolver) btran(v Vector) Vector {
+	y := append(Vector{}, v...)
+	for i := len(s.etas) - 1; i >= 0; i-- {
+		eta := s.etas[i]
+		value := y[eta.position]
+		for j, x := range eta.column {
+			if j != eta.position {
+				value -= x * y[j]
+			}
+		}
+		y[eta.position] = value / eta.column[eta.position]
+	}
+	return s.lu.SolveTranspose(y)
+}
+
+// dualVector computes the dual vector of the phase 1
+// system for the current costs.
+func (s *revisedSolver) dualVector() Vector {
+	basicCosts := make(Vector, len(s.basis))
+	for i, variable := range s.basis {
+		basicCosts[i] = s.costs[variable]
+	}
+	return s.btran(basicCosts)
+}
+
+func (s *revisedSolver) denseColumn(variable int) Vector {
+	res := make(Vector, len(s.basis))
+	for _, entry := range s.columns[variable] {
+		res[entry.row] = entry.value
+	}
+	return res
+}
+
+// removeArtificials pivots zero-valued artificial
+// variables out of the basis after phase 1.
+//
+// If no original variable can replace an artificial, the
+// row is redundant and the artificial stays basic, fixed
+// at zero, since it is never eligible to enter again.
+func (s *revisedSolver) removeArtificials() bool {
+	epsilon := s.epsilon()
+	for position, variable := range s.basis {
+		if variable < s.lp.Dim() {
+			continue
+		}
+		s.values[position] = 0
+		unit := make(Vector, len(s.basis))
+		unit[position] = 1
+		row := s.btran(unit)
+		for j := 0; j < s.lp.Dim(); j++ {
+			if s.isBasic[j] {
+				continue
+			}
+			var entry float64
+			for _, e := range s.columns[j] {
+				entry += row[e.row] * e.value
+			}
+			if math.Abs(entry) > epsilon {
+				if !s.pivot(position, j, s.ftran(s.denseColumn(j))) {
+					return false
+				}
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (s *revisedSolver) epsilon() float64 {
+	return relativeEpsilon * math.Max(1, math.Max(s.rhs.AbsMax(), s.costs.AbsMax()))
+}
+
+func (s *revisedSolver) objective() float64 {
+	var res float64
+	for i, variable := range s.basis {
+		res += s.costs[variable] * s.values[i]
+	}
+	return res
+}
+
+func (s *revisedSolver) solution() Vector {
+	res := make(Vector, s.lp.Dim())
+	for i, variable := range s.basis {
+		if variable < s.lp.Dim() {
+			res[variable] = s.values[i]
+		}
+	}
+	return res
+}
+
+func (s *revisedSolver) basisList() []int {
+	res := make([]int, len(s.basis))
+	for i, variable := range s.basis {
+		if variable < s.lp.Dim() {
+			res[i] = variable
+		} else {
+			res[i] = -1
+		}
+	}
+	return res
+}
+
+// duals converts the dual vector of the phase 1 system
+// into duals for the original rows.
+func (s *revisedSolver) duals() Vector {
+	res := s.dualVector()
+	for i, b := range s.lp.ConstraintVector {
+		if b < 0 {
+			res[i] *= -1
+		}
+	}
+	return res
+}
+
+func (s *revisedSolver) reducedCosts(duals Vector) Vector {
+	res := append(Vector{}, s.lp.Objective...)
+	for i, y := range duals {
+		if y != 0 {
+			res.Add(s.lp.ConstraintMatrix.CopyRow(i), -y)
+		}
+	}
+	for _, variable := range s.basis {
+		if variable < s.lp.Dim() {
+			res[variable] = 0
+		}
+	}
+	return res
+}
+
+func (s *revisedSolver) ray(entering int) Vector {
+	res := make(Vector, s.lp.Dim())
+	res[entering] = 1
+	column := s.ftran(s.denseColumn(entering))
+	for i, variable := range s.basis {
+		if variable < s.lp.Dim() {
+			res[variable] = -column[i]
+		}
+	}
+	return res
+}
+
+// farkas computes a certificate of infeasibility from an
+// optimal phase 1 basis.
+func (s *revisedSolver) farkas() Vector {
+	// The phase 1 duals w satisfy w'*S*A >= 0 and
+	// w'*S*b < 0, so y = -S*w is a certificate.
+	res := s.duals()
+	res.Scale(-1)
+	if dot := res.Dot(s.lp.ConstraintVector); dot > 0 {
+		res.Scale(1 / dot)
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+package linprog
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRevisedSimplex(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		problem := randomStandardLP(15, 30)
+		for _, rules := range []struct {
+			pivot   PivotRule
+			pricing PricingRule
+		}{
+			{BlandPivotRule{}, BlandPricingRule{}},
+			{GreedyPivotRule{}, GreedyPricingRule{}},
+		} {
+			expected := Simplex(problem, rules.pivot, true)
+			actual := RevisedSimplex(problem, rules.pricing)
+			if actual.Status != expected.Status {
+				t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+			}
+			if expected.Status != Optimal {
+				continue
+			}
+			if math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+				t.Errorf("expected objective %f but got %f", expected.Objective,
+					actual.Objective)
+			}
+			if math.Abs(actual.Duals.Dot(problem.ConstraintVector)-actual.Objective) > 1e-5 {
+				t.Errorf("duality gap for duals %v", actual.Duals)
+			}
+		}
+	}
+}
+
+func TestRevisedSimplexCertificates(t *testing.T) {
+	problem := &StandardLP{
+		Objective: Vector{4.5, 3.5},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 1,
+			NumCols: 2,
+			Data:    []float64{1, -1},
+		},
+		ConstraintVector: Vector{1},
+	}
+	res := RevisedSimplex(problem, GreedyPricingRule{})
+	if res.Status != Unbounded || !VerifyRay(problem, res.Ray) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Ray)
+	}
+
+	problem.ConstraintMatrix = &DenseMatrix{
+		NumRows: 2,
+		NumCols: 2,
+		Data:    []float64{1, -1, -2, 2},
+	}
+	problem.ConstraintVector = Vector{1, -1.5}
+	res = RevisedSimplex(problem, GreedyPricingRule{})
+	if res.Status != Infeasible || !VerifyFarkas(problem, res.Farkas) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Farkas)
+	}
+
+	problem.ConstraintVector = Vector{1, -2}
+	problem.Objective = Vector{-4.5, 3.5}
+	res = RevisedSimplex(problem, GreedyPricingRule{})
+	if res.Status != Optimal || !vectorsEqual(res.Solution, Vector{1, 0}) {
+		t.Errorf("unexpected result: %v %v", res.Status, res.Solution)
+	}
+}
+
+func BenchmarkRevisedSimplexRandom(b *testing.B) {
+	for _, size := range []int{10, 30, 50, 70, 90, 110} {
+		b.Run(fmt.Sprintf("Size%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				problem := randomStandardLP(size-1, size)
+				b.StartTimer()
+				RevisedSimplex(problem, GreedyPricingRule{})
+			}
+		})
+	}
+}
+
+// randomStandardLP creates a random feasible program.
+func randomStandardLP(rows, cols int) *StandardLP {
+	problem := &StandardLP{
+		Objective: NewVectorRandom(cols),
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: rows,
+			NumCols: cols,
+			Data:    NewVectorRandom(rows * cols),
+		},
+		ConstraintVector: make(Vector, rows),
+	}
+	values := NewVectorRandom(cols).Abs()
+	for i := range problem.ConstraintVector {
+		problem.ConstraintVector[i] = problem.ConstraintMatrix.CopyRow(i).Dot(values)
+	}
+	return problem
+}
@@ -0,0 +1,126 @@
+package linprog
+
+import "math"
+
+// A Range describes how far a coefficient may decrease or
+// increase without changing the optimal basis.
+// Either limit may be +Inf.
+type Range struct {
+	Decrease float64
+	Increase float64
+}
+
+// Contains checks if a change to the coefficient is
+// within the range.
+func (r Range) Contains(delta float64) bool {
+	return delta >= -r.Decrease && delta <= r.Increase
+}
+
+// Sensitivity stores the results of ranging analysis on
+// an optimal basis.
+type Sensitivity struct {
+	// Objective stores, for every variable, the range of
+	// changes to its objective coefficient over which the
+	// basis remains optimal.
+	Objective []Range
+
+	// RHS stores, for every constraint row, the range of
+	// changes to its right-hand side over which the basis
+	// remains feasible.
+	// Within this range, the objective changes at a rate
+	// given by the row's dual value.
+	RHS []Range
+}
+
+// Sensitivity performs ranging analysis on an optimal
+// phase 2 tableau.
+//
+// If the tableau was not produced by phase 1, if the
+// basis is numerically singular, or if the program has
+// upper bounds, nil is returned.
+func (s *SimplexTableau) Sensitivity() *Sensitivity {
+	lu := s.basisLU()
+	if lu == nil {
+		return nil
+	}
+	return s.sensitivity(lu)
+}
+
+// sensitivity is like Sensitivity, but it reuses a
+// factorization from basisLU.
+func (s *SimplexTableau) sensitivity(lu *LU) *Sensitivity {
+	if s.lp.hasUpperBounds() {
+		return nil
+	}
+	return &Sensitivity{
+		Objective: s.objectiveRanges(),
+		RHS:       s.rhsRanges(lu),
+	}
+}
+
+func (s *SimplexTableau) objectiveRanges() []Range {
+	costs := s.ReducedCosts()
+	res := make([]Range, s.Dim())
+	for i := range res {
+		row, ok := s.BasicToRow[i]
+		if !ok {
+			// Increasing the cost of a non-basic variable
+			// eventually makes it worth entering.
+			res[i] = Range{Decrease: math.Inf(1), Increase: math.Max(0, -costs[i])}
+			continue
+		}
+
+		// Changing the cost of a basic variable by delta
+		// changes every other reduced cost by -delta
+		// times the variable's row entry.
+		res[i] = Range{Decrease: math.Inf(1), Increase: math.Inf(1)}
+		entries := s.Matrix.CopyRow(row)
+		for j, cost := range costs {
+			entry := entries[j]
+			if s.Basic(j) || entry == 0 {
+				continue
+			}
+			limit := math.Max(0, -cost) / math.Abs(entry)
+			if entry > 0 {
+				res[i].Decrease = math.Min(res[i].Decrease, limit)
+			} else {
+				res[i].Increase = math.Min(res[i].Increase, limit)
+			}
+		}
+	}
+	return res
+}
+
+func (s *SimplexTableau) rhsRanges(lu *LU) []Range {
+	numRows := len(s.lp.ConstraintVector)
+	values := s.Matrix.CopyCol(s.Matrix.Cols() - 1)
+	epsilon := relativeEpsilon * s.Matrix.AbsMax()
+	res := make([]Range, numRows)
+	for i := range res {
+		res[i] = Range{Decrease: math.Inf(1), Increase: math.Inf(1)}
+
+		// Increasing b[i] by delta moves the basic values
+		// by delta*B^-1*e_i.
+		unit := make(Vector, numRows)
+		unit[i] = 1
+		direction := lu.Solve(unit)
+		for row, x := range direction {
+			if math.Abs(x) <= epsilon {
+				continue
+			}
+			if _, ok := s.RowToBasic[row]; !ok {
+				// Moving along a redundant row would make
+				// the constraints inconsistent.
+				res[i] = Range{}
+				break
+			}
+			limit := math.Max(0, values[row]) / math.Abs(x)
+			if x > 0 {
+				res[i].Decrease = math.Min(res[i].Decrease, limit)
+			} else {
+				res[i].Increase = math.Min(res[i].Increase, limit)
+			}
+		}
+	}
+	return res
+}
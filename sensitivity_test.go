@@ -0,0 +1,53 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSensitivity(t *testing.T) {
+	// Maximize 3x + 5y subject to x <= 4, 2y <= 12, and
+	// 3x + 2y <= 18, with slack variables.
+	problem := &StandardLP{
+		Objective: Vector{3, 5, 0, 0, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 3,
+			NumCols: 5,
+			Data: []float64{
+				1, 0, 1, 0, 0,
+				0, 2, 0, 1, 0,
+				3, 2, 0, 0, 1,
+			},
+		},
+		ConstraintVector: Vector{4, 12, 18},
+	}
+	res := Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Fatalf("unexpected status: %v", res.Status)
+	}
+	inf := math.Inf(1)
+	expectedObjective := []Range{{3, 4.5}, {3, inf}, {4.5, 3}, {inf, 1.5}, {inf, 1}}
+	expectedRHS := []Range{{2, inf}, {6, 6}, {6, 6}}
+	for i, expected := range expectedObjective {
+		if !rangesEqual(res.Sensitivity.Objective[i], expected) {
+			t.Errorf("objective %d: expected %v but got %v", i, expected,
+				res.Sensitivity.Objective[i])
+		}
+	}
+	for i, expected := range expectedRHS {
+		if !rangesEqual(res.Sensitivity.RHS[i], expected) {
+			t.Errorf("rhs %d: expected %v but got %v", i, expected, res.Sensitivity.RHS[i])
+		}
+	}
+}
+
+func rangesEqual(r1, r2 Range) bool {
+	return limitsEqual(r1.Decrease, r2.Decrease) && limitsEqual(r1.Increase, r2.Increase)
+}
+
+func limitsEqual(x, y float64) bool {
+	if math.IsInf(x, 1) || math.IsInf(y, 1) {
+		return x == y
+	}
+	return math.Abs(x-y) < 1e-5
+}
@@ -0,0 +1,510 @@
+// Package server implements an HTTP service for solving
+// linear programs with the simplex method.
+//
+// Programs are sent in the JSON format of linprog.Model,
+// and results are returned as a Result, which refers to
+// the model's variables and constraints rather than to
+// its compiled standard form.
+//
+// The service has the following endpoints:
+//
+//	POST   /solve      solve a program and wait for the result
+//	POST   /jobs       submit a program, returning its job
+//	GET    /jobs/{id}  get the state of a job and its result
+//	DELETE /jobs/{id}  cancel a job
+//
+// Solver options are passed as query parameters:
+//
+//	pivot           bland, greedy, steepest, or devex
+//	ratio           standard, harris, or lex
+//	dense           true to use a dense tableau
+//	perturbation    relative size of the RHS perturbation
+//	max_iterations  maximum number of pivots
+//	time_limit      maximum solve time, such as "10s"
+//
+// A job is in one of the states "queued", "running",
+// "done", or "cancelled". A job which is cancelled while
+// it is running keeps its partial result, whose status is
+// Cancelled.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unixpickle/linprog"
+)
+
+// maxBodySize is the largest request body that the server
+// will read.
+const maxBodySize = 64 << 20
+
+// Job states.
+const (
+	StateQueued    = "queued"
+	StateRunning   = "running"
+	StateDone      = "done"
+	StateCancelled = "cancelled"
+)
+
+// Config configures a Server.
+// Zero fields are replaced with defaults.
+type Config struct {
+	// Workers is the number of programs which are solved
+	// at once. The default is runtime.NumCPU().
+	Workers int
+
+	// QueueSize is the number of jobs which may wait for a
+	// worker before new jobs are rejected.
+	// The default is 64.
+	QueueSize int
+
+	// JobTTL is how long the result of a finished job is
+	// kept for polling. The default is 10 minutes.
+	JobTTL time.Duration
+}
+
+// A Server is an http.Handler which solves programs with a
+// pool of workers.
+type Server struct {
+	config Config
+	queue  chan *job
+	wg     sync.WaitGroup
+
+	// ctx is the parent of every job's context, and it is
+	// cancelled by Close.
+	ctx  context.Context
+	stop context.CancelFunc
+
+	// solve solves a program, and is replaced in tests.
+	solve func(ctx context.Context, model *linprog.Model, opts *solveOptions) *Result
+
+	lock   sync.Mutex
+	jobs   map[string]*job
+	closed bool
+}
+
+// New creates a Server and starts its workers.
+// If config is nil, default settings are used.
+//
+// The server should be closed with Close once it is no
+// longer needed.
+func New(config *Config) *Server {
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 64
+	}
+	if c.JobTTL <= 0 {
+		c.JobTTL = 10 * time.Minute
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	s := &Server{
+		config: c,
+		queue:  make(chan *job, c.QueueSize),
+		ctx:    ctx,
+		stop:   stop,
+		solve:  solveModel,
+		jobs:   map[string]*job{},
+	}
+	for i := 0; i < c.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	return s
+}
+
+// Close cancels every job and stops the workers.
+// Requests which arrive after Close fail.
+func (s *Server) Close() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.closed = true
+	s.stop()
+	close(s.queue)
+	s.lock.Unlock()
+	s.wg.Wait()
+}
+
+// ServeHTTP routes a request to an endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/solve":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.handleSolve(w, r)
+	case r.URL.Path == "/jobs":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.handleSubmit(w, r)
+	case strings.HasPrefix(r.URL.Path, "/jobs/"):
+		id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+		switch r.Method {
+		case http.MethodGet:
+			s.handleStatus(w, id)
+		case http.MethodDelete:
+			s.handleCancel(w, id)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) {
+	j, err := newJob(s.ctx, w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.enqueue(j, false); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	select {
+	case <-j.done:
+	case <-r.Context().Done():
+		// The client is gone, so the result is not needed.
+		j.cancel()
+		return
+	}
+	res := j.snapshot().Result
+	if res == nil {
+		writeError(w, http.StatusServiceUnavailable, "job cancelled")
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	j, err := newJob(s.ctx, w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.enqueue(j, true); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, j.snapshot())
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, id string) {
+	j := s.lookup(id)
+	if j == nil {
+		writeError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	writeJSON(w, http.StatusOK, j.snapshot())
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, id string) {
+	j := s.lookup(id)
+	if j == nil {
+		writeError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	j.lock.Lock()
+	if j.state == StateQueued {
+		// Workers skip cancelled jobs, so the job is
+		// finished right away.
+		j.finish(StateCancelled, nil)
+	}
+	j.cancelled = true
+	j.lock.Unlock()
+	j.cancel()
+	writeJSON(w, http.StatusOK, j.snapshot())
+}
+
+// enqueue adds a job to the queue, and registers it for
+// polling if store is true.
+func (s *Server) enqueue(j *job, store bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errors.New("server is closed")
+	}
+	s.removeExpired()
+	select {
+	case s.queue <- j:
+	default:
+		return errors.New("queue is full")
+	}
+	if store {
+		s.jobs[j.id] = j
+	}
+	return nil
+}
+
+func (s *Server) lookup(id string) *job {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.removeExpired()
+	return s.jobs[id]
+}
+
+// removeExpired forgets jobs which finished more than
+// JobTTL ago. The caller must hold s.lock.
+func (s *Server) removeExpired() {
+	now := time.Now()
+	for id, j := range s.jobs {
+		j.lock.Lock()
+		expired := !j.finished.IsZero() && now.Sub(j.finished) > s.config.JobTTL
+		j.lock.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *Server) worker() {
+	defer s.wg.Done()
+	for j := range s.queue {
+		j.lock.Lock()
+		if j.state != StateQueued || j.ctx.Err() != nil {
+			j.finish(StateCancelled, nil)
+			j.lock.Unlock()
+			continue
+		}
+		j.state = StateRunning
+		j.lock.Unlock()
+
+		res := s.solve(j.ctx, j.model, j.opts)
+
+		j.lock.Lock()
+		if j.cancelled || j.ctx.Err() != nil {
+			j.finish(StateCancelled, res)
+		} else {
+			j.finish(StateDone, res)
+		}
+		j.lock.Unlock()
+		j.cancel()
+	}
+}
+
+// A job is a program waiting to be solved, or one which
+// has been solved.
+type job struct {
+	id     string
+	model  *linprog.Model
+	opts   *solveOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	lock      sync.Mutex
+	state     string
+	cancelled bool
+	result    *Result
+	finished  time.Time
+}
+
+// jobJSON is the JSON encoding of a job.
+type jobJSON struct {
+	ID     string  `json:"id"`
+	State  string  `json:"state"`
+	Result *Result `json:"result,omitempty"`
+}
+
+func newJob(ctx context.Context, w http.ResponseWriter, r *http.Request) (*job, error) {
+	opts, err := parseOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	model := linprog.NewModel()
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := decoder.Decode(model); err != nil {
+		return nil, fmt.Errorf("invalid model: %s", err)
+	}
+	var idBytes [16]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &job{
+		id:     hex.EncodeToString(idBytes[:]),
+		model:  model,
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		state:  StateQueued,
+	}, nil
+}
+
+// finish records the final state of the job.
+// The caller must hold j.lock.
+func (j *job) finish(state string, res *Result) {
+	if !j.finished.IsZero() {
+		return
+	}
+	j.state = state
+	j.result = res
+	j.finished = time.Now()
+	close(j.done)
+}
+
+func (j *job) snapshot() *jobJSON {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return &jobJSON{ID: j.id, State: j.state, Result: j.result}
+}
+
+// solveOptions stores the solver options from the query
+// parameters of a request.
+type solveOptions struct {
+	pivot        string
+	ratio        string
+	dense        bool
+	perturbation float64
+	maxIter      int
+	timeLimit    time.Duration
+}
+
+func parseOptions(r *http.Request) (*solveOptions, error) {
+	query := r.URL.Query()
+	opts := &solveOptions{pivot: "greedy", ratio: "standard"}
+	if p := query.Get("pivot"); p != "" {
+		opts.pivot = p
+	}
+	if rt := query.Get("ratio"); rt != "" {
+		opts.ratio = rt
+	}
+	if _, err := opts.pivotRule(); err != nil {
+		return nil, err
+	}
+	var err error
+	if d := query.Get("dense"); d != "" {
+		if opts.dense, err = strconv.ParseBool(d); err != nil {
+			return nil, fmt.Errorf("invalid dense: %s", d)
+		}
+	}
+	if p := query.Get("perturbation"); p != "" {
+		if opts.perturbation, err = strconv.ParseFloat(p, 64); err != nil {
+			return nil, fmt.Errorf("invalid perturbation: %s", p)
+		}
+	}
+	if m := query.Get("max_iterations"); m != "" {
+		if opts.maxIter, err = strconv.Atoi(m); err != nil {
+			return nil, fmt.Errorf("invalid max_iterations: %s", m)
+		}
+	}
+	if t := query.Get("time_limit"); t != "" {
+		if opts.timeLimit, err = time.ParseDuration(t); err != nil {
+			return nil, fmt.Errorf("invalid time_limit: %s", t)
+		}
+	}
+	return opts, nil
+}
+
+func (s *solveOptions) pivotRule() (linprog.PivotRule, error) {
+	rt, err := linprog.ParseRatioTest(s.ratio)
+	if err != nil {
+		return nil, err
+	}
+	return linprog.ParsePivotRule(s.pivot, rt)
+}
+
+// A Result is the outcome of solving a model.
+//
+// Values, ReducedCosts, and Ray are indexed by the model's
+// variables, and Duals by its constraints, in the order
+// they were added. Basis is the exception: it lists
+// columns of the model's compiled standard form.
+//
+// Objective is omitted unless the status is Optimal or the
+// solver was stopped early with a solution. Farkas
+// certificates are left out, since they may involve rows
+// which compilation adds for variable bounds.
+type Result struct {
+	Status           linprog.SimplexStatus `json:"status"`
+	Objective        *float64              `json:"objective,omitempty"`
+	Values           linprog.Vector        `json:"values,omitempty"`
+	Duals            linprog.Vector        `json:"duals,omitempty"`
+	ReducedCosts     linprog.Vector        `json:"reduced_costs,omitempty"`
+	Ray              linprog.Vector        `json:"ray,omitempty"`
+	Basis            []int                 `json:"compiled_basis,omitempty"`
+	Phase1Iterations int                   `json:"phase1_iterations"`
+	Phase2Iterations int                   `json:"phase2_iterations"`
+	Perturbed        bool                  `json:"perturbed,omitempty"`
+}
+
+// solveModel solves a model, translating the result into
+// the model's variables and constraints.
+func solveModel(ctx context.Context, model *linprog.Model, opts *solveOptions) *Result {
+	lp, mapping := model.Compile()
+	pr, _ := opts.pivotRule()
+	simplexOpts := &linprog.Options{
+		Context:       ctx,
+		Perturbation:  opts.perturbation,
+		MaxIterations: opts.maxIter,
+	}
+	if opts.timeLimit > 0 {
+		simplexOpts.Deadline = time.Now().Add(opts.timeLimit)
+	}
+	res := linprog.SimplexWithOptions(lp, pr, opts.dense, simplexOpts)
+	solution := model.Solution(mapping, res)
+	modelRes := &Result{
+		Status:           res.Status,
+		Values:           solution.Values,
+		Duals:            solution.Duals,
+		ReducedCosts:     solution.ReducedCosts,
+		Basis:            res.Basis,
+		Phase1Iterations: res.Phase1Iterations,
+		Phase2Iterations: res.Phase2Iterations,
+		Perturbed:        res.Perturbed,
+	}
+	switch res.Status {
+	case linprog.Optimal, linprog.IterationLimit, linprog.Cancelled:
+		if solution.Values != nil {
+			modelRes.Objective = &solution.Objective
+		}
+	}
+	if res.Ray != nil {
+		// Compilation may append variables for range
+		// constraints, which are not part of the model.
+		modelRes.Ray = mapping.Ray(res.Ray)[:len(model.Vars())]
+	}
+	return modelRes
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unixpickle/linprog"
+)
+
+const testModel = `{
+	"sense": "max",
+	"objective": [3, 2],
+	"matrix": {"rows": [0, 0, 1, 1], "cols": [0, 1, 0, 1], "values": [1, 1, 1, 3]},
+	"rhs": [4, 6],
+	"relations": ["<=", "<="],
+	"upper_bounds": [3, null],
+	"variable_names": ["x", "y"]
+}`
+
+func TestSolve(t *testing.T) {
+	s, server := testServer(t, &Config{Workers: 2})
+	defer s.Close()
+
+	for _, query := range []string{"", "?pivot=steepest&ratio=harris&dense=true",
+		"?pivot=bland&ratio=lex&perturbation=1e-6&time_limit=1m"} {
+		var res Result
+		code := request(t, http.MethodPost, server.URL+"/solve"+query, testModel, &res)
+		if code != http.StatusOK {
+			t.Fatalf("%q: unexpected status code %d", query, code)
+		}
+		if res.Status != linprog.Optimal || res.Objective == nil ||
+			math.Abs(*res.Objective-11) > 1e-8 {
+			t.Errorf("%q: unexpected result: %+v", query, res)
+		}
+		if len(res.Values) != 2 || math.Abs(res.Values[0]-3) > 1e-8 ||
+			math.Abs(res.Values[1]-1) > 1e-8 {
+			t.Errorf("%q: unexpected solution: %v", query, res.Values)
+		}
+		if len(res.Duals) != 2 || len(res.ReducedCosts) != 2 {
+			t.Errorf("%q: unexpected duals: %v %v", query, res.Duals, res.ReducedCosts)
+		}
+	}
+
+	var res Result
+	code := request(t, http.MethodPost, server.URL+"/solve?max_iterations=1", testModel, &res)
+	if code != http.StatusOK || res.Status != linprog.IterationLimit {
+		t.Errorf("unexpected result: %d %v", code, res.Status)
+	}
+
+	// Maximize x - y subject to x - y >= 1 and y <= 2,
+	// where x is free and y >= -1, so the ray is (1, 0).
+	unbounded := `{"sense": "max", "objective": [1, -1], "rhs": [1, 2],
+		"relations": [">=", "<="], "lower_bounds": [null, -1],
+		"matrix": {"rows": [0, 0, 1], "cols": [0, 1, 1], "values": [1, -1, 1]}}`
+	var body map[string]interface{}
+	code = request(t, http.MethodPost, server.URL+"/solve", unbounded, &body)
+	if code != http.StatusOK || body["status"] != "Unbounded" {
+		t.Errorf("unexpected result: %d %v", code, body)
+	} else if _, ok := body["objective"]; ok {
+		t.Errorf("unexpected objective: %v", body)
+	} else if ray, ok := body["ray"].([]interface{}); !ok || len(ray) != 2 ||
+		ray[0].(float64) <= 0 || math.Abs(ray[1].(float64)) > 1e-8 {
+		t.Errorf("unexpected ray: %v", body["ray"])
+	}
+
+	for _, c := range []struct {
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{http.MethodPost, "/solve", `{"objective": [1], "rhs": [1], "matrix": {"rows": [2]}}`,
+			http.StatusBadRequest},
+		{http.MethodPost, "/solve?pivot=random", testModel, http.StatusBadRequest},
+		{http.MethodPost, "/solve?max_iterations=x", testModel, http.StatusBadRequest},
+		{http.MethodGet, "/solve", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/jobs/missing", "", http.StatusNotFound},
+		{http.MethodGet, "/other", "", http.StatusNotFound},
+	} {
+		var body map[string]string
+		if code := request(t, c.method, server.URL+c.path, c.body, &body); code != c.code {
+			t.Errorf("%s %s: expected status %d but got %d", c.method, c.path, c.code, code)
+		} else if body["error"] == "" {
+			t.Errorf("%s %s: missing error message", c.method, c.path)
+		}
+	}
+}
+
+func TestJobs(t *testing.T) {
+	s, server := testServer(t, &Config{Workers: 1})
+	defer s.Close()
+
+	var submitted jobJSON
+	code := request(t, http.MethodPost, server.URL+"/jobs", testModel, &submitted)
+	if code != http.StatusAccepted || submitted.ID == "" {
+		t.Fatalf("unexpected response: %d %+v", code, submitted)
+	}
+	job := waitForJob(t, server.URL, submitted.ID)
+	if job.State != StateDone || job.Result == nil || job.Result.Status != linprog.Optimal {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestJobCancellation(t *testing.T) {
+	s, server := testServer(t, &Config{Workers: 1, QueueSize: 2})
+	defer s.Close()
+
+	started := make(chan struct{}, 10)
+	s.solve = func(ctx context.Context, model *linprog.Model,
+		opts *solveOptions) *Result {
+		started <- struct{}{}
+		<-ctx.Done()
+		return &Result{Status: linprog.Cancelled}
+	}
+
+	var running, queued jobJSON
+	request(t, http.MethodPost, server.URL+"/jobs", testModel, &running)
+	<-started
+	request(t, http.MethodPost, server.URL+"/jobs", testModel, &queued)
+
+	var job jobJSON
+	request(t, http.MethodGet, server.URL+"/jobs/"+running.ID, "", &job)
+	if job.State != StateRunning {
+		t.Errorf("expected running job but got %+v", job)
+	}
+
+	// The worker is busy, so the queue fills up.
+	request(t, http.MethodPost, server.URL+"/jobs", testModel, &jobJSON{})
+	code := request(t, http.MethodPost, server.URL+"/jobs", testModel, &map[string]string{})
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected a full queue but got status %d", code)
+	}
+
+	request(t, http.MethodDelete, server.URL+"/jobs/"+queued.ID, "", &job)
+	if job.State != StateCancelled || job.Result != nil {
+		t.Errorf("unexpected queued job after cancellation: %+v", job)
+	}
+
+	request(t, http.MethodDelete, server.URL+"/jobs/"+running.ID, "", &job)
+	job = *waitForJob(t, server.URL, running.ID)
+	if job.State != StateCancelled || job.Result == nil ||
+		job.Result.Status != linprog.Cancelled {
+		t.Errorf("unexpected running job after cancellation: %+v", job)
+	}
+}
+
+func TestJobExpiry(t *testing.T) {
+	s, server := testServer(t, &Config{JobTTL: time.Millisecond})
+	defer s.Close()
+
+	var submitted jobJSON
+	request(t, http.MethodPost, server.URL+"/jobs", testModel, &submitted)
+	waitForJob(t, server.URL, submitted.ID)
+	time.Sleep(10 * time.Millisecond)
+	var body map[string]string
+	code := request(t, http.MethodGet, server.URL+"/jobs/"+submitted.ID, "", &body)
+	if code != http.StatusNotFound {
+		t.Errorf("expected expired job but got status %d", code)
+	}
+}
+
+func testServer(t *testing.T, config *Config) (*Server, *httptest.Server) {
+	s := New(config)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func waitForJob(t *testing.T, url, id string) *jobJSON {
+	for i := 0; i < 1000; i++ {
+		var job jobJSON
+		if code := request(t, http.MethodGet, url+"/jobs/"+id, "", &job); code != http.StatusOK {
+			t.Fatalf("unexpected status code %d", code)
+		}
+		if job.State == StateDone || job.State == StateCancelled {
+			return &job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("job did not finish")
+	return nil
+}
+
+func request(t *testing.T, method, url, body string, result interface{}) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
@@ -1,26 +1,128 @@
 package linprog
 
-// Simplex runs the simplex algorithm to completion and
-// returns a solution if one is found. If there is no
-// solution, nil is returned and the boolean return value
-// is true if the problem is unbounded, or false if the
-// problem has no feasible solutions.
-func Simplex(lp *StandardLP, pr PivotRule, dense bool) (Vector, bool) {
-	tableau := SimplexPhase1(lp, pr, dense)
+import "math"
+
+// A Result is the outcome of solving a linear program.
+type Result struct {
+	Status SimplexStatus
+
+	// Solution is the final primal solution.
+	// If Status is Unbounded, it is the feasible point at
+	// which the unbounded direction was found.
+	// If Status is IterationLimit or Cancelled, it is the
+	// last feasible point, or nil if the algorithm stopped
+	// before finding one.
+	// Otherwise, it is nil unless Status is Optimal.
+	Solution Vector
+
+	// Objective is the objective value of Solution.
+	Objective float64
+
+	// Phase1Iterations and Phase2Iterations count the
+	// pivots performed in each phase of the algorithm.
+	Phase1Iterations int
+	Phase2Iterations int
+
+	// Basis stores the basic variable for each constraint
+	// row, or -1 for rows that were dropped as redundant.
+	// It is nil if phase 1 did not find a feasible basis.
+	Basis []int
+
+	// Duals and ReducedCosts are the dual vector and the
+	// reduced costs at the optimal basis.
+	// See SimplexTableau.Duals and ReducedCosts.
+	// They are nil unless Status is Optimal.
+	Duals        Vector
+	ReducedCosts Vector
+
+	// Sensitivity is the ranging analysis of the optimal
+	// basis. It is nil unless Status is Optimal, and it is
+	// always nil for programs with upper bounds, whose
+	// ranges also depend on the bounds. To analyze such a
+	// program, solve the one from BoundsToRows instead.
+	//
+	// It reuses the basis factorization from Duals, adding
+	// one solve per constraint row.
+	Sensitivity *Sensitivity
+
+	// Ray is a direction of unbounded improvement.
+	// See SimplexTableau.Ray.
+	// It is nil unless Status is Unbounded.
+	Ray Vector
+
+	// Farkas is a certificate of infeasibility.
+	// See SimplexTableau.FarkasCertificate.
+	// It is nil unless Status is Infeasible.
+	Farkas Vector
+
+	// Perturbed indicates that the result came from a
+	// perturbed right-hand side; see Options.Perturbation.
+	// It is false if perturbation was requested but the
+	// original program had to be solved instead.
+	Perturbed bool
+}
+
+// Simplex runs the simplex algorithm to completion.
+//
+// If Status is Optimal, the result includes an optimal
+// solution. Otherwise, the status indicates why no
+// solution was found.
+func Simplex(lp *StandardLP, pr PivotRule, dense bool) *Result {
+	_, res := SimplexWithTableau(lp, pr, dense)
+	return res
+}
+
+// SimplexWithTableau is like Simplex, but it also returns
+// the final phase 2 tableau, so that it can be used for
+// further analysis, or re-solved with DualSimplex after a
+// call to SetConstraintVector.
+//
+// The tableau is nil if no feasible basis was found.
+func SimplexWithTableau(lp *StandardLP, pr PivotRule, dense bool) (*SimplexTableau, *Result) {
+	res := &Result{}
+	tableau := simplexPhase1(lp, pr, dense, res)
 	if tableau == nil {
-		return nil, false
+		return nil, res
 	}
-	for {
-		leaving, entering, status := pr.ChoosePivot(tableau)
-		if status == Unbounded {
-			return nil, true
-		} else if status == Optimal {
-			break
-		} else {
-			tableau.Pivot(leaving, entering)
+	status, entering := runSimplex(tableau, pr, &res.Phase2Iterations)
+	tableau.fillResult(res, status, entering)
+	return tableau, res
+}
+
+// fillResult records the final state of a phase 2
+// tableau in res.
+//
+// If status is Unbounded, entering is the variable which
+// can be increased without bound.
+func (s *SimplexTableau) fillResult(res *Result, status SimplexStatus, entering int) {
+	res.Status = status
+	res.Basis = s.Basis()
+	if status == Unbounded {
+		res.Solution = s.Solution()
+		res.Objective = s.ObjectiveValue()
+		res.Ray = s.Ray(entering)
+	} else if status == Optimal {
+		res.Solution = s.Solution()
+		res.Objective = s.ObjectiveValue()
+		if lu := s.basisLU(); lu != nil {
+			res.Duals = s.duals(lu)
+			res.Sensitivity = s.sensitivity(lu)
+		}
+		res.ReducedCosts = s.ReducedCosts()
+		if !finiteVector(res.Solution) {
+			res.Status = NumericalFailure
 		}
+	} else if interrupted(status) {
+		// Phase 2 tableaus are always feasible.
+		res.Solution = s.Solution()
+		res.Objective = s.ObjectiveValue()
 	}
-	return tableau.Solution(), true
+}
+
+// interrupted checks if a status means that the algorithm
+// was stopped early.
+func interrupted(status SimplexStatus) bool {
+	return status == IterationLimit || status == Cancelled
 }
 
 // SimplexPhase1 runs phase 1 of the simplex algorithm to
@@ -31,24 +133,99 @@ func Simplex(lp *StandardLP, pr PivotRule, dense bool) (Vector, bool) {
 // If no basic feasible solution can be found, nil is
 // returned.
 func SimplexPhase1(lp *StandardLP, pr PivotRule, dense bool) *SimplexTableau {
+	return simplexPhase1(lp, pr, dense, &Result{})
+}
+
+// simplexPhase1 is like SimplexPhase1, but it records the
+// number of iterations in res.
+// If it fails, it also records the status and, if the
+// program is infeasible, a Farkas certificate.
+func simplexPhase1(lp *StandardLP, pr PivotRule, dense bool, res *Result) *SimplexTableau {
 	tableau := NewTableauPhase1(lp, dense)
-	for {
-		leaving, entering, status := pr.ChoosePivot(tableau)
-		if status == Unbounded {
-			return nil
-		} else if status == Optimal {
-			break
-		} else {
-			tableau.Pivot(leaving, entering)
-		}
+	if status := runPhase1(tableau, pr, &res.Phase1Iterations); status != Optimal {
+		res.Status = status
+		return nil
 	}
 	eps := tableau.Matrix.AbsMax() * relativeEpsilon
-	if tableau.ObjectiveValue() > eps {
+	if tableau.ObjectiveValue() < -eps {
+		res.Status = Infeasible
+		res.Farkas = tableau.FarkasCertificate()
 		return nil
 	}
 
 	if !tableau.phase1ToPhase2(lp) {
+		res.Status = Infeasible
 		return nil
 	}
 	return tableau
 }
+
+// runPhase1 is like runSimplex, but for a phase 1 tableau,
+// which cannot be unbounded.
+func runPhase1(t *SimplexTableau, pr PivotRule, iterations *int) SimplexStatus {
+	status, _ := runSimplex(t, pr, iterations)
+	if status == Unbounded {
+		// The phase 1 objective is bounded above by zero,
+		// so the entering variable's relative cost must be
+		// a rounding error, which repricing removes.
+		t.repricePhase1()
+		status, _ = runSimplex(t, pr, iterations)
+	}
+	if status == Unbounded {
+		return NumericalFailure
+	}
+	return status
+}
+
+// repricePhase1 recomputes the relative cost coefficients
+// and the objective value of a phase 1 tableau from its
+// constraint rows, discarding the rounding error which
+// accumulates in the cost row over many pivots.
+func (s *SimplexTableau) repricePhase1() {
+	costRow := s.Matrix.Rows() - 1
+	costs := make(Vector, s.Matrix.Cols())
+	for i := s.lp.Dim(); i < s.Dim(); i++ {
+		costs[i] = -1
+	}
+	for row, basic := range s.RowToBasic {
+		if basic >= s.lp.Dim() {
+			costs.Add(s.Matrix.CopyRow(row), 1)
+		}
+	}
+	for i, cost := range costs {
+		s.Matrix.Set(costRow, i, cost)
+	}
+}
+
+// runSimplex pivots until the pivot rule indicates that
+// the algorithm should stop, returning the final status.
+// If the status is Unbounded, it also returns the
+// variable which can be increased without bound.
+func runSimplex(t *SimplexTableau, pr PivotRule, iterations *int) (SimplexStatus, int) {
+	for {
+		leaving, entering, status := pr.ChoosePivot(t)
+		if status != Working {
+			return status, entering
+		}
+		if leaving != entering {
+			coeff := t.Matrix.At(t.BasicToRow[leaving], entering)
+			if coeff == 0 || math.IsNaN(coeff) || math.IsInf(coeff, 0) {
+				return NumericalFailure, -1
+			}
+		}
+		if observer, ok := pr.(PivotObserver); ok {
+			observer.ObservePivot(t, leaving, entering)
+		}
+		t.Pivot(leaving, entering)
+		*iterations++
+	}
+}
+
+func finiteVector(v Vector) bool {
+	for _, x := range v {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return false
+		}
+	}
+	return true
+}
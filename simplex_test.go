@@ -2,6 +2,7 @@ package linprog
 
 import (
 	"fmt"
+	"math"
 	"testing"
 )
 
@@ -16,18 +17,22 @@ func TestSimplex2D(t *testing.T) {
 		},
 		ConstraintVector: Vector{1},
 	}
-	solution, ok := Simplex(problem, BlandPivotRule{}, false)
-	if solution == nil || !ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
-	} else if !vectorsEqual(solution, Vector{1, 0}) {
-		t.Errorf("unexpected solution: %v", solution)
+	res := Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Errorf("unexpected status: %v", res.Status)
+	} else if !vectorsEqual(res.Solution, Vector{1, 0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
 	}
 
 	// Maximize 4.5x + 3.5y, subject to x-y = 1
 	problem.Objective = Vector{4.5, 3.5}
-	solution, ok = Simplex(problem, BlandPivotRule{}, false)
-	if solution != nil || !ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
+	res = Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Unbounded {
+		t.Errorf("unexpected status: %v", res.Status)
+	} else if !VerifyRay(problem, res.Ray) {
+		t.Errorf("invalid ray: %v", res.Ray)
+	} else if !vectorsEqual(res.Solution, Vector{1, 0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
 	}
 
 	// Maximize 4.5x + 3.5y, subject to x-y = 1 and 2x-2y = 1.5.
@@ -37,19 +42,19 @@ func TestSimplex2D(t *testing.T) {
 		Data:    []float64{1, -1, 2, -2},
 	}
 	problem.ConstraintVector = Vector{1, 1.5}
-	solution, ok = Simplex(problem, BlandPivotRule{}, false)
-	if solution != nil || ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
+	res = Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Infeasible {
+		t.Errorf("unexpected status: %v", res.Status)
 	}
 
 	// Maximize -4.5x + 3.5y, subject to x-y = 1 and 2x - 2y = 2.
 	problem.Objective = Vector{-4.5, 3.5}
 	problem.ConstraintVector = Vector{1, 2}
-	solution, ok = Simplex(problem, BlandPivotRule{}, false)
-	if solution == nil || !ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
-	} else if !vectorsEqual(solution, Vector{1, 0}) {
-		t.Errorf("unexpected solution: %v", solution)
+	res = Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Errorf("unexpected status: %v", res.Status)
+	} else if !vectorsEqual(res.Solution, Vector{1, 0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
 	}
 }
 
@@ -65,11 +70,15 @@ func TestSimplex3D(t *testing.T) {
 		},
 		ConstraintVector: Vector{10, 15},
 	}
-	solution, ok := Simplex(problem, BlandPivotRule{}, false)
-	if solution == nil || !ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
-	} else if !vectorsEqual(solution, Vector{15.0 / 7.0, 0, 25.0 / 7.0}) {
-		t.Errorf("unexpected solution: %v", solution)
+	res := Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Errorf("unexpected status: %v", res.Status)
+	} else if !vectorsEqual(res.Solution, Vector{15.0 / 7.0, 0, 25.0 / 7.0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
+	} else if math.Abs(res.Objective-130.0/7.0) > 1e-5 {
+		t.Errorf("unexpected objective: %f", res.Objective)
+	} else if len(res.Basis) != 2 || res.Phase1Iterations == 0 {
+		t.Errorf("unexpected basis %v after %d iterations", res.Basis, res.Phase1Iterations)
 	}
 }
 
@@ -89,11 +98,43 @@ func TestSimplex6D(t *testing.T) {
 		},
 		ConstraintVector: Vector{14, 28, 30},
 	}
-	solution, ok := Simplex(problem, BlandPivotRule{}, false)
-	if solution == nil || !ok {
-		t.Errorf("unexpected return %v %v", solution, ok)
-	} else if !vectorsEqual(solution, Vector{5, 4, 0, 0, 0, 0}) {
-		t.Errorf("unexpected solution: %v", solution)
+	res := Simplex(problem, BlandPivotRule{}, false)
+	if res.Status != Optimal {
+		t.Errorf("unexpected status: %v", res.Status)
+	} else if !vectorsEqual(res.Solution, Vector{5, 4, 0, 0, 0, 0}) {
+		t.Errorf("unexpected solution: %v", res.Solution)
+	}
+}
+
+func TestSimplexPhase1Reprice(t *testing.T) {
+	// Rounding error often makes phase 1 look unbounded
+	// on these programs, even though they are feasible.
+	for i := 0; i < 100; i++ {
+		problem := randomStandardLP(10, 20)
+		res := &Result{}
+		if simplexPhase1(problem, GreedyPivotRule{}, true, res) == nil {
+			t.Fatalf("unexpected status: %v", res.Status)
+		}
+	}
+}
+
+func TestRevisedSimplexPhase1Reprice(t *testing.T) {
+	// After x0 enters, the column of x1 has a tiny entry
+	// in the artificial row next to a huge one, so phase 1
+	// cannot tell that x1 may enter.
+	problem := &StandardLP{
+		Objective: Vector{0, 0},
+		ConstraintMatrix: &DenseMatrix{
+			NumRows: 2,
+			NumCols: 2,
+			Data:    Vector{1, -1e9, 0, 1},
+		},
+		ConstraintVector: Vector{1, 1},
+	}
+	for _, rule := range []PricingRule{BlandPricingRule{}, GreedyPricingRule{}} {
+		if res := RevisedSimplex(problem, rule); res.Status == Infeasible {
+			t.Errorf("feasible program reported infeasible with certificate %v", res.Farkas)
+		}
 	}
 }
 
@@ -29,6 +29,23 @@ type SimplexTableau struct {
 	//
 	// If a variable is missing, it is non-basic.
 	BasicToRow map[int]int
+
+	// lp is the program which the tableau was created
+	// from, if known.
+	lp *StandardLP
+
+	// complemented stores the variables which have been
+	// replaced by their upper bound minus themselves.
+	// Their columns and costs in the tableau refer to the
+	// complement rather than the original variable.
+	complemented map[int]bool
+
+	// lexBasis stores the basic variables of each row at
+	// the start of the current phase. Their columns hold
+	// the inverse of the current basis relative to the
+	// initial one, which LexicographicRatioTest uses to
+	// break ties.
+	lexBasis []int
 }
 
 // NewTableauPhase1 creates a SimplexTableau by wrapping a
@@ -76,12 +93,14 @@ func NewTableauPhase1(lp *StandardLP, dense bool) *SimplexTableau {
 		Matrix:     matrix,
 		RowToBasic: map[int]int{},
 		BasicToRow: map[int]int{},
+		lp:         lp,
 	}
 	for i := 0; i < numConstraints; i++ {
 		basic := lp.Dim() + i
 		res.BasicToRow[basic] = i
 		res.RowToBasic[i] = basic
 	}
+	res.lexBasis = res.basicVariables()
 	return res
 }
 
@@ -92,10 +111,38 @@ func (s *SimplexTableau) Dim() int {
 
 // Pivot takes two variables, one which is basic and one
 // which is not, and swaps their roles.
+//
+// For programs with upper bounds, the leaving variable
+// may equal the entering variable, in which case the
+// variable moves to its opposite bound without a change
+// of basis. If the pivot entry is negative, the leaving
+// variable leaves at its upper bound rather than at zero.
 func (s *SimplexTableau) Pivot(leaving, entering int) {
+	if leaving == entering {
+		s.complement(entering)
+		return
+	}
+	toUpper := s.Matrix.At(s.BasicToRow[leaving], entering) < 0 &&
+		!math.IsInf(s.upperBound(leaving), 1)
+	s.swapBasis(leaving, entering)
+	if toUpper {
+		s.complement(leaving)
+	}
+}
+
+// swapBasis is like Pivot, but it ignores upper bounds.
+func (s *SimplexTableau) swapBasis(leaving, entering int) {
 	row := s.BasicToRow[leaving]
-	column := entering
+	s.eliminate(row, entering)
+	s.RowToBasic[row] = entering
+	delete(s.BasicToRow, leaving)
+	s.BasicToRow[entering] = row
+}
 
+// eliminate scales a row so that its entry in the column
+// is 1, and then uses it to zero out the column's entries
+// in every other row.
+func (s *SimplexTableau) eliminate(row, column int) {
 	coeff := s.Matrix.At(row, column)
 	s.Matrix.ScaleRow(row, 1/coeff)
 
@@ -105,16 +152,16 @@ func (s *SimplexTableau) Pivot(leaving, entering int) {
 		}
 		s.Matrix.AddRow(row, i, -s.Matrix.At(i, column))
 	}
-
-	s.RowToBasic[row] = entering
-	delete(s.BasicToRow, leaving)
-	s.BasicToRow[entering] = row
 }
 
 // ObjectiveValue gets the current value of the objective
 // function.
+//
+// The tableau stores the negated objective value, since
+// it is obtained by subtracting constraint rows from the
+// cost row.
 func (s *SimplexTableau) ObjectiveValue() float64 {
-	return s.Matrix.At(s.Matrix.Rows()-1, s.Matrix.Cols()-1)
+	return -s.Matrix.At(s.Matrix.Rows()-1, s.Matrix.Cols()-1)
 }
 
 // Cost gets the relative cost coefficient for a variable.
@@ -134,12 +181,41 @@ func (s *SimplexTableau) Basic(i int) bool {
 	return res
 }
 
+// Basis gets the basic variable for each constraint row,
+// or -1 for rows that were dropped as redundant.
+func (s *SimplexTableau) Basis() []int {
+	res := make([]int, s.Matrix.Rows()-1)
+	for row := range res {
+		if basic, ok := s.RowToBasic[row]; ok {
+			res[row] = basic
+		} else {
+			res[row] = -1
+		}
+	}
+	return res
+}
+
+// basicVariables lists the basic variables in order of
+// their rows, skipping rows without a basic variable.
+func (s *SimplexTableau) basicVariables() []int {
+	var res []int
+	for _, basic := range s.Basis() {
+		if basic != -1 {
+			res = append(res, basic)
+		}
+	}
+	return res
+}
+
 // Solution gets the current solution vector.
 func (s *SimplexTableau) Solution() Vector {
 	res := make(Vector, s.Dim())
 	for basic, row := range s.BasicToRow {
 		res[basic] = s.Matrix.At(row, s.Matrix.Cols()-1)
 	}
+	for variable := range s.complemented {
+		res[variable] = s.upperBound(variable) - res[variable]
+	}
 	return res
 }
 
@@ -184,7 +260,7 @@ func (s *SimplexTableau) phase1ToPhase2(lp *StandardLP) bool {
 		for real := range nonBasic {
 			if math.Abs(s.Matrix.At(row, real)) > epsilon {
 				delete(nonBasic, real)
-				s.Pivot(artificial, real)
+				s.swapBasis(artificial, real)
 				found = true
 				break
 			}
@@ -212,9 +288,19 @@ func (s *SimplexTableau) phase1ToPhase2(lp *StandardLP) bool {
 		},
 	}
 
+	// Complemented variables have negated costs, and they
+	// contribute a constant to the objective.
+	costRow := s.Matrix.Rows() - 1
+	for variable := range s.complemented {
+		cost := lp.Objective[variable]
+		s.Matrix.Set(costRow, variable, -cost)
+		s.Matrix.Set(costRow, lp.Dim(), s.Matrix.At(costRow, lp.Dim())-cost*lp.UpperBound(variable))
+	}
+
 	for basic, row := range s.BasicToRow {
-		s.Matrix.AddRow(row, s.Matrix.Rows()-1, -s.Cost(basic))
+		s.Matrix.AddRow(row, costRow, -s.Cost(basic))
 	}
+	s.lexBasis = s.basicVariables()
 
 	return true
 }
@@ -0,0 +1,168 @@
+package linprog
+
+import "math"
+
+// NewTableauBasis creates a phase 2 tableau in which the
+// given variables are basic, skipping phase 1 entirely.
+//
+// The basis may come from the Basis field of a previous
+// Result, in which case entries of -1 are ignored.
+//
+// Basis variables whose columns are linearly dependent on
+// the others are skipped. Rows left without a basic
+// variable are then filled by a crash procedure, which
+// picks any non-basic variable with a non-zero entry in
+// the row. Rows which are entirely zero are dropped as
+// redundant.
+//
+// The resulting basis may be infeasible, i.e. it may have
+// negative basic values.
+// If the constraints are found to be inconsistent, nil is
+// returned.
+func NewTableauBasis(lp *StandardLP, basis []int) *SimplexTableau {
+	numRows := len(lp.ConstraintVector)
+	matrix := RowBlockMatrix{
+		ColumnBlockMatrix{lp.ConstraintMatrix.Copy(), lp.ConstraintVector.Col().Copy()},
+		&DenseMatrix{
+			NumRows: 1,
+			NumCols: lp.Dim() + 1,
+			Data:    append(append([]float64{}, lp.Objective...), 0),
+		},
+	}
+	res := &SimplexTableau{
+		Matrix:     matrix,
+		RowToBasic: map[int]int{},
+		BasicToRow: map[int]int{},
+		lp:         lp,
+	}
+
+	epsilon := relativeEpsilon * matrix.AbsMax()
+	for _, variable := range basis {
+		if variable >= 0 && !res.Basic(variable) {
+			res.eliminateFreeRow(variable, epsilon)
+		}
+	}
+	for variable := 0; variable < lp.Dim() && len(res.RowToBasic) < numRows; variable++ {
+		if !res.Basic(variable) {
+			res.eliminateFreeRow(variable, epsilon)
+		}
+	}
+
+	valueCol := matrix.Cols() - 1
+	for row := 0; row < numRows; row++ {
+		if _, ok := res.RowToBasic[row]; ok {
+			continue
+		}
+		if math.Abs(matrix.At(row, valueCol)) > epsilon {
+			return nil
+		}
+		matrix.ScaleRow(row, 0)
+	}
+	res.lexBasis = res.basicVariables()
+	return res
+}
+
+// eliminateFreeRow makes a variable basic in the row
+// without a basic variable which has the largest entry in
+// the variable's column.
+//
+// If every such entry is zero, the variable is left
+// non-basic.
+func (s *SimplexTableau) eliminateFreeRow(variable int, epsilon float64) {
+	column := s.Matrix.CopyCol(variable)
+	bestRow := -1
+	bestAbs := epsilon
+	for row, entry := range column[:len(column)-1] {
+		if _, ok := s.RowToBasic[row]; ok {
+			continue
+		}
+		if abs := math.Abs(entry); abs > bestAbs {
+			bestRow = row
+			bestAbs = abs
+		}
+	}
+	if bestRow == -1 {
+		return
+	}
+	s.eliminate(bestRow, variable)
+	s.RowToBasic[bestRow] = variable
+	s.BasicToRow[variable] = bestRow
+}
+
+// WarmSimplex is like Simplex, but it starts from a basis
+// supplied by the caller, such as the basis of a
+// previously solved program with the same dimensions.
+//
+// If the basis is singular, it is completed with
+// NewTableauBasis.
+// If the resulting basis is feasible, phase 2 starts
+// immediately. Otherwise, if the basis is dual feasible,
+// it is repaired with the dual simplex method. As a last
+// resort, the program is solved from scratch with Simplex.
+// Dual simplex pivots are counted as phase 1 iterations.
+func WarmSimplex(lp *StandardLP, basis []int, pr PivotRule, dense bool) *Result {
+	res := &Result{}
+	finishSimplex(lp, NewTableauBasis(lp, basis), pr, dense, res)
+	return res
+}
+
+// finishSimplex runs the simplex method to completion
+// from a phase 2 tableau, repairing it with the dual
+// simplex method or starting from scratch if it is not
+// feasible. A nil tableau also starts from scratch.
+//
+// It returns the final tableau, or nil if phase 1 did not
+// find a feasible basis.
+func finishSimplex(lp *StandardLP, tableau *SimplexTableau, pr PivotRule, dense bool,
+	res *Result) *SimplexTableau {
+	if tableau != nil && !repairTableau(tableau, res) {
+		tableau = nil
+	}
+	if tableau == nil {
+		*res = Result{}
+		if tableau = simplexPhase1(lp, pr, dense, res); tableau == nil {
+			return nil
+		}
+	}
+	status, entering := runSimplex(tableau, pr, &res.Phase2Iterations)
+	tableau.fillResult(res, status, entering)
+	return tableau
+}
+
+// repairTableau makes a phase 2 tableau feasible with the
+// dual simplex method if it is dual feasible, counting
+// dual pivots as phase 1 iterations.
+//
+// It returns false if the tableau could not be made
+// feasible, in which case it should be discarded.
+func repairTableau(tableau *SimplexTableau, res *Result) bool {
+	epsilon := relativeEpsilon * tableau.Matrix.AbsMax()
+	if tableau.primalFeasible(epsilon) {
+		return true
+	}
+	if !tableau.dualFeasible(epsilon) {
+		return false
+	}
+	status, _ := runDualSimplex(tableau, GreedyDualPivotRule{}, &res.Phase1Iterations)
+	return status == Optimal
+}
+
+func (s *SimplexTableau) primalFeasible(epsilon float64) bool {
+	valueCol := s.Matrix.Cols() - 1
+	for row, basic := range s.RowToBasic {
+		value := s.Matrix.At(row, valueCol)
+		if value < -epsilon || value > s.upperBound(basic)+epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SimplexTableau) dualFeasible(epsilon float64) bool {
+	for i, cost := range s.Costs() {
+		if cost > epsilon && !s.Basic(i) {
+			return false
+		}
+	}
+	return true
+}
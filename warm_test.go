@@ -0,0 +1,78 @@
+package linprog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWarmSimplex(t *testing.T) {
+	var numRepaired int
+	for i := 0; i < 30; i++ {
+		problem := randomStandardLP(10, 20)
+		initial := Simplex(problem, GreedyPivotRule{}, true)
+		if initial.Status != Optimal {
+			continue
+		}
+
+		// Objective changes keep the basis feasible.
+		objectiveChange := NewVectorRandom(problem.Dim())
+		problem.Objective.Add(objectiveChange, 0.1)
+		testWarmSimplex(t, problem, initial.Basis, true)
+
+		// RHS changes keep the basis dual feasible once the
+		// objective is restored. The new RHS comes from a
+		// different feasible point.
+		problem.Objective.Add(objectiveChange, -0.1)
+		values := NewVectorRandom(problem.Dim()).Abs()
+		for i := range problem.ConstraintVector {
+			problem.ConstraintVector[i] = problem.ConstraintMatrix.CopyRow(i).Dot(values)
+		}
+		if testDualRepair(t, problem, initial.Basis) {
+			numRepaired++
+		}
+		testWarmSimplex(t, problem, initial.Basis, false)
+
+		// Incomplete bases are completed by the crash.
+		testWarmSimplex(t, problem, initial.Basis[:3], false)
+	}
+	if numRepaired == 0 {
+		t.Error("no basis was repaired with the dual simplex method")
+	}
+}
+
+func testWarmSimplex(t *testing.T, problem *StandardLP, basis []int, skipPhase1 bool) {
+	expected := Simplex(problem, GreedyPivotRule{}, true)
+	actual := WarmSimplex(problem, basis, GreedyPivotRule{}, true)
+	if actual.Status != expected.Status {
+		t.Fatalf("expected status %v but got %v", expected.Status, actual.Status)
+	}
+	if skipPhase1 && actual.Phase1Iterations != 0 {
+		t.Errorf("expected no phase 1 iterations but got %d", actual.Phase1Iterations)
+	}
+	if expected.Status == Optimal && math.Abs(actual.Objective-expected.Objective) > 1e-5 {
+		t.Errorf("expected objective %f but got %f", expected.Objective, actual.Objective)
+	}
+}
+
+// testDualRepair checks that a dual feasible basis is
+// repaired with dual simplex pivots rather than being
+// discarded. It returns false if the basis was already
+// feasible, or if the program has no optimal solution.
+func testDualRepair(t *testing.T, problem *StandardLP, basis []int) bool {
+	tableau := NewTableauBasis(problem, basis)
+	if tableau.primalFeasible(relativeEpsilon * tableau.Matrix.AbsMax()) {
+		return false
+	}
+	if Simplex(problem, GreedyPivotRule{}, true).Status != Optimal {
+		return false
+	}
+	res := &Result{}
+	if !repairTableau(tableau, res) {
+		t.Error("basis was not repaired")
+		return false
+	}
+	if res.Phase1Iterations == 0 {
+		t.Error("expected dual pivots")
+	}
+	return true
+}